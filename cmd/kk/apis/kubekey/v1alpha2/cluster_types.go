@@ -36,6 +36,7 @@ import (
 // ClusterSpec defines the desired state of Cluster
 type ClusterSpec struct {
 	Hosts                []HostCfg            `yaml:"hosts" json:"hosts,omitempty"`
+	Bastions             []BastionCfg         `yaml:"bastions,omitempty" json:"bastions,omitempty"`
 	RoleGroups           map[string][]string  `yaml:"roleGroups" json:"roleGroups,omitempty"`
 	ControlPlaneEndpoint ControlPlaneEndpoint `yaml:"controlPlaneEndpoint" json:"controlPlaneEndpoint,omitempty"`
 	System               System               `yaml:"system" json:"system,omitempty"`
@@ -43,6 +44,7 @@ type ClusterSpec struct {
 	Kubernetes           Kubernetes           `yaml:"kubernetes" json:"kubernetes,omitempty"`
 	Network              NetworkConfig        `yaml:"network" json:"network,omitempty"`
 	Registry             RegistryConfig       `yaml:"registry" json:"registry,omitempty"`
+	Storage              Storage              `yaml:"storage" json:"storage,omitempty"`
 	Addons               []Addon              `yaml:"addons" json:"addons,omitempty"`
 	KubeSphere           KubeSphere           `json:"kubesphere,omitempty"`
 }
@@ -66,11 +68,25 @@ type HostCfg struct {
 	PrivateKeyPath  string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
 	Arch            string `yaml:"arch,omitempty" json:"arch,omitempty"`
 	Timeout         *int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Bastions overrides the cluster-wide jump hosts for this host.
+	Bastions []BastionCfg `yaml:"bastions,omitempty" json:"bastions,omitempty"`
 
 	// Labels defines the kubernetes labels for the node.
 	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
 }
 
+// BastionCfg defines a jump host for the ssh connections. The bastions of the cluster or a host are ordered
+// from the first hop to the last hop. The credentials of the target host are used if neither password nor private key is set.
+type BastionCfg struct {
+	Address        string `yaml:"address,omitempty" json:"address,omitempty"`
+	Port           int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User           string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password       string `yaml:"password,omitempty" json:"password,omitempty"`
+	PrivateKey     string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
+	PrivateKeyPath string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
+	Timeout        *int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
 // ControlPlaneEndpoint defines the control plane endpoint information for cluster.
 type ControlPlaneEndpoint struct {
 	InternalLoadbalancer string  `yaml:"internalLoadbalancer" json:"internalLoadbalancer,omitempty"`
@@ -78,18 +94,74 @@ type ControlPlaneEndpoint struct {
 	Address              string  `yaml:"address" json:"address,omitempty"`
 	Port                 int     `yaml:"port" json:"port,omitempty"`
 	KubeVip              KubeVip `yaml:"kubevip" json:"kubevip,omitempty"`
+	// Keepalived configures the VRRP instance of the loadbalancer role group, the Address is used as the VIP.
+	Keepalived Keepalived `yaml:"keepalived" json:"keepalived,omitempty"`
 }
 
 type KubeVip struct {
 	Mode string `yaml:"mode" json:"mode,omitempty"`
 }
 
+// Keepalived defines the VRRP settings of the haproxy and keepalived pair running on the loadbalancer nodes.
+type Keepalived struct {
+	// Interface is the network interface which the VIP is bound to, it's detected by the internal address of the node if empty.
+	Interface       string `yaml:"interface" json:"interface,omitempty"`
+	VirtualRouterID int    `yaml:"virtualRouterId" json:"virtualRouterId,omitempty"`
+	// AuthPass enables the PASS authentication of VRRP, only the first 8 characters are used.
+	AuthPass string `yaml:"authPass" json:"authPass,omitempty"`
+}
+
 // System defines the system config for each node in cluster.
 type System struct {
 	NtpServers []string `yaml:"ntpServers" json:"ntpServers,omitempty"`
 	Timezone   string   `yaml:"timezone" json:"timezone,omitempty"`
 	Rpms       []string `yaml:"rpms" json:"rpms,omitempty"`
 	Debs       []string `yaml:"debs" json:"debs,omitempty"`
+	// Sysctl adds or overrides the kernel parameters written to /etc/sysctl.d/99-kubekey.conf.
+	Sysctl map[string]string `yaml:"sysctl" json:"sysctl,omitempty"`
+	// Modules are the kernel modules loaded on boot by /etc/modules-load.d/kubekey.conf, besides the built-in ones.
+	Modules []string `yaml:"modules" json:"modules,omitempty"`
+	// Limits are written to /etc/security/limits.d/99-kubekey.conf, besides the built-in ones.
+	Limits []Limit      `yaml:"limits" json:"limits,omitempty"`
+	Tweaks SystemTweaks `yaml:"tweaks" json:"tweaks,omitempty"`
+}
+
+// Limit is a line of limits.conf(5).
+type Limit struct {
+	Domain string `yaml:"domain" json:"domain,omitempty"`
+	Type   string `yaml:"type" json:"type,omitempty"`
+	Item   string `yaml:"item" json:"item,omitempty"`
+	Value  string `yaml:"value" json:"value,omitempty"`
+}
+
+// SystemTweaks toggles the built-in changes made by the init os script, all of them are enabled by default
+// except Limits and IptablesLegacy, which must be enabled explicitly.
+type SystemTweaks struct {
+	SwapOff         *bool `yaml:"swapOff" json:"swapOff,omitempty"`
+	DisableSELinux  *bool `yaml:"disableSELinux" json:"disableSELinux,omitempty"`
+	DisableFirewall *bool `yaml:"disableFirewall" json:"disableFirewall,omitempty"`
+	// KernelParams are the built-in sysctls, e.g. net.ipv4.ip_forward and vm.max_map_count.
+	KernelParams *bool `yaml:"kernelParams" json:"kernelParams,omitempty"`
+	// KernelModules are the built-in modules: br_netfilter, overlay, ipvs and nf_conntrack.
+	KernelModules *bool `yaml:"kernelModules" json:"kernelModules,omitempty"`
+	// Limits are the built-in nofile and nproc limits of 65535.
+	Limits *bool `yaml:"limits" json:"limits,omitempty"`
+	// IptablesLegacy switches the iptables utilities to the legacy backend by update-alternatives.
+	IptablesLegacy *bool `yaml:"iptablesLegacy" json:"iptablesLegacy,omitempty"`
+	DropCaches     *bool `yaml:"dropCaches" json:"dropCaches,omitempty"`
+}
+
+// Enabled returns true if the tweak is not disabled explicitly.
+func (t *SystemTweaks) Enabled(tweak *bool) bool {
+	if tweak == nil {
+		return true
+	}
+	return *tweak
+}
+
+// EnabledExplicitly returns true only if the tweak is enabled explicitly.
+func (t *SystemTweaks) EnabledExplicitly(tweak *bool) bool {
+	return tweak != nil && *tweak
 }
 
 // RegistryConfig defines the configuration information of the image's repository.
@@ -161,6 +233,11 @@ func (cfg *ClusterSpec) GroupHosts() map[string][]*KubeHost {
 	if len(roleGroups[Registry]) > 1 {
 		logger.Log.Fatal(errors.New("The number of registry node cannot be greater than 1."))
 	}
+	for _, host := range roleGroups[Loadbalancer] {
+		if host.IsRole(Master) || host.IsRole(ControlPlane) {
+			logger.Log.Fatal(errors.Errorf("The loadbalancer node %s cannot be a master/control-plane node.", host.Name))
+		}
+	}
 
 	for _, host := range roleGroups[ControlPlane] {
 		host.SetRole(Master)
@@ -188,6 +265,20 @@ func toHosts(cfg HostCfg) *KubeHost {
 	host.PrivateKeyPath = cfg.PrivateKeyPath
 	host.Arch = cfg.Arch
 	host.Timeout = *cfg.Timeout
+	for _, b := range cfg.Bastions {
+		bastion := connector.Bastion{
+			Address:        b.Address,
+			Port:           b.Port,
+			User:           b.User,
+			Password:       b.Password,
+			PrivateKey:     b.PrivateKey,
+			PrivateKeyPath: b.PrivateKeyPath,
+		}
+		if b.Timeout != nil {
+			bastion.Timeout = *b.Timeout
+		}
+		host.Bastions = append(host.Bastions, bastion)
+	}
 
 	kubeHost := &KubeHost{
 		BaseHost: host,
@@ -276,8 +367,14 @@ func hostVerify(hostMap map[string]*KubeHost, hostName string, group string) err
 	return nil
 }
 
+// IsInternalLBEnabled is used to determine whether a load balancer of the apiservers runs on each worker,
+// i.e. haproxy, nginx or envoy.
 func (c ControlPlaneEndpoint) IsInternalLBEnabled() bool {
-	return c.InternalLoadbalancer == Haproxy
+	switch c.InternalLoadbalancer {
+	case Haproxy, Nginx, Envoy:
+		return true
+	}
+	return false
 }
 
 func (c ControlPlaneEndpoint) IsInternalLBEnabledVip() bool {
@@ -44,6 +44,7 @@ const (
 	DefaultDockerVersion        = "20.10.8"
 	DefaultContainerdVersion    = "1.6.4"
 	DefaultRuncVersion          = "v1.1.1"
+	DefaultCrioVersion          = "1.24.1"
 	DefaultCrictlVersion        = "v1.24.0"
 	DefaultKubeVersion          = "v1.23.10"
 	DefaultCalicoVersion        = "v3.23.2"
@@ -73,6 +74,8 @@ const (
 	Worker                      = "worker"
 	K8s                         = "k8s"
 	Registry                    = "registry"
+	Loadbalancer                = "loadbalancer"
+	DefaultVRRPVirtualRouterID  = 51
 	DefaultEtcdBackupDir        = "/var/backups/kube_etcd"
 	DefaultEtcdBackupPeriod     = 30
 	DefaultKeepBackNumber       = 5
@@ -90,6 +93,12 @@ const (
 	DefaultDNSAddress           = "114.114.114.114"
 	DefaultDpdkTunnelIface      = "br-phy"
 	DefaultCNIConfigPriority    = "01"
+	DefaultNFSStorageClass      = "nfs-client"
+	DefaultLocalPathStorageDir  = "/opt/local-path-provisioner"
+	DefaultLocalPathClass       = "local-path"
+	DefaultLonghornDataPath     = "/var/lib/longhorn"
+	DefaultLonghornReplicaCount = 3
+	DefaultLonghornClass        = "longhorn"
 
 	Docker     = "docker"
 	Conatinerd = "containerd"
@@ -97,6 +106,8 @@ const (
 	Isula      = "isula"
 
 	Haproxy            = "haproxy"
+	Nginx              = "nginx"
+	Envoy              = "envoy"
 	Kubevip            = "kube-vip"
 	DefaultKubeVipMode = "ARP"
 )
@@ -105,14 +116,16 @@ func (cfg *ClusterSpec) SetDefaultClusterSpec() (*ClusterSpec, map[string][]*Kub
 	clusterCfg := ClusterSpec{}
 
 	clusterCfg.Hosts = SetDefaultHostsCfg(cfg)
+	clusterCfg.Bastions = cfg.Bastions
 	clusterCfg.RoleGroups = cfg.RoleGroups
 	clusterCfg.Etcd = SetDefaultEtcdCfg(cfg)
 	roleGroups := clusterCfg.GroupHosts()
-	clusterCfg.ControlPlaneEndpoint = SetDefaultLBCfg(cfg, roleGroups[Master])
+	clusterCfg.ControlPlaneEndpoint = SetDefaultLBCfg(cfg, roleGroups[Master], roleGroups[Loadbalancer])
 	clusterCfg.Network = SetDefaultNetworkCfg(cfg)
 	clusterCfg.System = cfg.System
 	clusterCfg.Kubernetes = SetDefaultClusterCfg(cfg)
 	clusterCfg.Registry = cfg.Registry
+	clusterCfg.Storage = SetDefaultStorageCfg(cfg)
 	clusterCfg.Addons = cfg.Addons
 	clusterCfg.KubeSphere = cfg.KubeSphere
 
@@ -175,14 +188,50 @@ func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
 			host.Timeout = &timeout
 		}
 
+		if len(host.Bastions) == 0 {
+			host.Bastions = cfg.Bastions
+		}
+		host.Bastions = SetDefaultBastionsCfg(host.Bastions, host)
+
 		hostCfg = append(hostCfg, host)
 	}
 	return hostCfg
 }
 
-func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup []*KubeHost) ControlPlaneEndpoint {
+// SetDefaultBastionsCfg fills the port, user and timeout of the jump hosts, the user and timeout default to the target host's.
+func SetDefaultBastionsCfg(bastions []BastionCfg, host HostCfg) []BastionCfg {
+	if len(bastions) == 0 {
+		return nil
+	}
+	bastionCfg := make([]BastionCfg, 0, len(bastions))
+	for _, bastion := range bastions {
+		if bastion.Port == 0 {
+			bastion.Port = DefaultSSHPort
+		}
+		if bastion.User == "" {
+			bastion.User = host.User
+		}
+		if bastion.PrivateKeyPath != "" && strings.HasPrefix(strings.TrimSpace(bastion.PrivateKeyPath), "~/") {
+			homeDir, _ := util.Home()
+			bastion.PrivateKeyPath = strings.Replace(bastion.PrivateKeyPath, "~/", fmt.Sprintf("%s/", homeDir), 1)
+		}
+		if bastion.Timeout == nil {
+			bastion.Timeout = host.Timeout
+		}
+		bastionCfg = append(bastionCfg, bastion)
+	}
+	return bastionCfg
+}
+
+func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup, loadbalancerGroup []*KubeHost) ControlPlaneEndpoint {
+	//The address is used as the VIP of the keepalived on the loadbalancer nodes
+	if len(loadbalancerGroup) != 0 && (cfg.ControlPlaneEndpoint.Address == "" || cfg.ControlPlaneEndpoint.IsInternalLBEnabled() || cfg.ControlPlaneEndpoint.IsInternalLBEnabledVip()) {
+		fmt.Println("When the loadbalancer role group is set, the LB address must be set to the VIP and the internal loadbalancer must be disabled.")
+		os.Exit(0)
+	}
+
 	//The detection is not an HA environment, and the address at LB does not need input
-	if len(masterGroup) == 1 && cfg.ControlPlaneEndpoint.Address != "" {
+	if len(masterGroup) == 1 && len(loadbalancerGroup) == 0 && cfg.ControlPlaneEndpoint.Address != "" {
 		fmt.Println("When the environment is not HA, the LB address does not need to be entered, so delete the corresponding value.")
 		os.Exit(0)
 	}
@@ -211,6 +260,9 @@ func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup []*KubeHost) ControlPlaneEndp
 	if cfg.ControlPlaneEndpoint.KubeVip.Mode == "" {
 		cfg.ControlPlaneEndpoint.KubeVip.Mode = DefaultKubeVipMode
 	}
+	if cfg.ControlPlaneEndpoint.Keepalived.VirtualRouterID == 0 {
+		cfg.ControlPlaneEndpoint.Keepalived.VirtualRouterID = DefaultVRRPVirtualRouterID
+	}
 	defaultLbCfg := cfg.ControlPlaneEndpoint
 	return defaultLbCfg
 }
@@ -323,6 +375,29 @@ func SetDefaultClusterCfg(cfg *ClusterSpec) Kubernetes {
 	return defaultClusterCfg
 }
 
+func SetDefaultStorageCfg(cfg *ClusterSpec) Storage {
+	storage := cfg.Storage
+	if storage.NFS.StorageClassName == "" {
+		storage.NFS.StorageClassName = DefaultNFSStorageClass
+	}
+	if storage.LocalPath.Path == "" {
+		storage.LocalPath.Path = DefaultLocalPathStorageDir
+	}
+	if storage.LocalPath.StorageClassName == "" {
+		storage.LocalPath.StorageClassName = DefaultLocalPathClass
+	}
+	if storage.Longhorn.DataPath == "" {
+		storage.Longhorn.DataPath = DefaultLonghornDataPath
+	}
+	if storage.Longhorn.ReplicaCount == 0 {
+		storage.Longhorn.ReplicaCount = DefaultLonghornReplicaCount
+	}
+	if storage.Longhorn.StorageClassName == "" {
+		storage.Longhorn.StorageClassName = DefaultLonghornClass
+	}
+	return storage
+}
+
 func SetDefaultEtcdCfg(cfg *ClusterSpec) EtcdCluster {
 	if cfg.Etcd.Type == "" || ((cfg.Kubernetes.Type == "k3s" || (len(strings.Split(cfg.Kubernetes.Version, "-")) > 1) && strings.Split(cfg.Kubernetes.Version, "-")[1] == "k3s") && cfg.Etcd.Type == Kubeadm) {
 		cfg.Etcd.Type = KubeKey
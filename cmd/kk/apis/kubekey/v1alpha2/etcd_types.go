@@ -31,6 +31,8 @@ type EtcdCluster struct {
 	BackupPeriod     int          `yaml:"backupPeriod" json:"backupPeriod,omitempty"`
 	KeepBackupNumber int          `yaml:"keepBackupNumber" json:"keepBackupNumber,omitempty"`
 	BackupScriptDir  string       `yaml:"backupScript" json:"backupScript,omitempty"`
+	// PKI provides the CA issued by an external PKI instead of the self-signed one when type is set to kubekey
+	PKI PKI `yaml:"pki" json:"pki,omitempty"`
 }
 
 // ExternalEtcd describes how to connect to an external etcd cluster
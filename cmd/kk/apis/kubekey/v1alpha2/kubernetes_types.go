@@ -46,6 +46,37 @@ type Kubernetes struct {
 	FeatureGates             map[string]bool      `yaml:"featureGates" json:"featureGates,omitempty"`
 	KubeletConfiguration     runtime.RawExtension `yaml:"kubeletConfiguration" json:"kubeletConfiguration,omitempty"`
 	KubeProxyConfiguration   runtime.RawExtension `yaml:"kubeProxyConfiguration" json:"kubeProxyConfiguration,omitempty"`
+	// PKI provides the CAs issued by an external PKI instead of the self-signed ones.
+	PKI PKI `yaml:"pki" json:"pki,omitempty"`
+}
+
+// PKI provides the CAs issued by an external PKI, and the certificates issued by them if the keys of the CAs are not provided.
+type PKI struct {
+	// CA is /etc/kubernetes/pki/ca.crt for kubernetes and /etc/ssl/etcd/ssl/ca.pem for etcd.
+	CA *CertificateAuthority `yaml:"ca" json:"ca,omitempty"`
+	// FrontProxyCA is /etc/kubernetes/pki/front-proxy-ca.crt, it's only used by kubernetes.
+	FrontProxyCA *CertificateAuthority `yaml:"frontProxyCA" json:"frontProxyCA,omitempty"`
+	// CertsDir holds the certificates issued by the CAs whose keys are not provided.
+	// For kubernetes, it contains a dir per control-plane node in the layout of /etc/kubernetes, e.g. <node>/pki/apiserver.crt and <node>/admin.conf.
+	// For etcd, it contains the files in the layout of /etc/ssl/etcd/ssl, e.g. member-<node>.pem and member-<node>-key.pem.
+	CertsDir string `yaml:"certsDir" json:"certsDir,omitempty"`
+}
+
+// CertificateAuthority is a CA on the local machine.
+type CertificateAuthority struct {
+	CertFile string `yaml:"certFile" json:"certFile,omitempty"`
+	// KeyFile can be omitted in the external CA mode, where the certificates issued by the CA are provided in the CertsDir.
+	KeyFile string `yaml:"keyFile" json:"keyFile,omitempty"`
+}
+
+// Enabled is used to determine whether any CA is provided.
+func (p *PKI) Enabled() bool {
+	return p.CA != nil || p.FrontProxyCA != nil
+}
+
+// ExternalCA is used to determine whether a CA is provided without its key.
+func (p *PKI) ExternalCA() bool {
+	return (p.CA != nil && p.CA.KeyFile == "") || (p.FrontProxyCA != nil && p.FrontProxyCA.KeyFile == "")
 }
 
 // Kata contains the configuration for the kata in cluster
@@ -82,8 +113,10 @@ func (k *Kubernetes) EnableNodeFeatureDiscovery() bool {
 	return *k.NodeFeatureDiscovery.Enabled
 }
 
+// EnableAutoRenewCerts is used to determine whether to renew the certificates by kubeadm periodically,
+// which is impossible in the external CA mode.
 func (k *Kubernetes) EnableAutoRenewCerts() bool {
-	if k.AutoRenewCerts == nil {
+	if k.AutoRenewCerts == nil || k.PKI.ExternalCA() {
 		return false
 	}
 	return *k.AutoRenewCerts
@@ -104,6 +104,8 @@ type ManifestSpec struct {
 	Components              Components               `yaml:"components" json:"components"`
 	Images                  []string                 `yaml:"images" json:"images"`
 	ManifestRegistry        ManifestRegistry         `yaml:"registry" json:"registry"`
+	// Addons are the charts and the remote yaml packed into the artifact, the images referenced by them are added to Images.
+	Addons []Addon `yaml:"addons" json:"addons,omitempty"`
 }
 
 // Manifest is the Schema for the manifests API
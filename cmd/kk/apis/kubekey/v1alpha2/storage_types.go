@@ -0,0 +1,110 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha2
+
+const (
+	OpenEBS   = "openebs"
+	NFS       = "nfs"
+	LocalPath = "local-path"
+	Longhorn  = "longhorn"
+)
+
+// Storage defines the storage class providers deployed in the cluster.
+type Storage struct {
+	// DefaultStorageClass is the provider whose StorageClass is the cluster default: openebs, nfs, local-path or longhorn.
+	// It defaults to the first enabled provider in the order nfs, local-path, longhorn.
+	DefaultStorageClass string           `yaml:"defaultStorageClass" json:"defaultStorageClass,omitempty"`
+	NFS                 NFSStorage       `yaml:"nfs" json:"nfs,omitempty"`
+	LocalPath           LocalPathStorage `yaml:"localPath" json:"localPath,omitempty"`
+	Longhorn            LonghornStorage  `yaml:"longhorn" json:"longhorn,omitempty"`
+}
+
+// NFSStorage is the nfs-subdir-external-provisioner against an existing NFS export.
+type NFSStorage struct {
+	Enabled          *bool    `yaml:"enabled" json:"enabled,omitempty"`
+	Server           string   `yaml:"server" json:"server,omitempty"`
+	Path             string   `yaml:"path" json:"path,omitempty"`
+	StorageClassName string   `yaml:"storageClassName" json:"storageClassName,omitempty"`
+	MountOptions     []string `yaml:"mountOptions" json:"mountOptions,omitempty"`
+	// ArchiveOnDelete keeps the data of the deleted volumes in a directory prefixed with "archived-".
+	ArchiveOnDelete bool `yaml:"archiveOnDelete" json:"archiveOnDelete,omitempty"`
+}
+
+// LocalPathStorage is the Rancher local-path-provisioner.
+type LocalPathStorage struct {
+	Enabled          *bool  `yaml:"enabled" json:"enabled,omitempty"`
+	Path             string `yaml:"path" json:"path,omitempty"`
+	StorageClassName string `yaml:"storageClassName" json:"storageClassName,omitempty"`
+}
+
+// LonghornStorage is the Longhorn distributed block storage, it requires open-iscsi on every node.
+type LonghornStorage struct {
+	Enabled          *bool  `yaml:"enabled" json:"enabled,omitempty"`
+	DataPath         string `yaml:"dataPath" json:"dataPath,omitempty"`
+	ReplicaCount     int    `yaml:"replicaCount" json:"replicaCount,omitempty"`
+	StorageClassName string `yaml:"storageClassName" json:"storageClassName,omitempty"`
+}
+
+// EnableNFS is used to determine whether to deploy the nfs-subdir-external-provisioner.
+func (s *Storage) EnableNFS() bool {
+	if s.NFS.Enabled == nil {
+		return false
+	}
+	return *s.NFS.Enabled
+}
+
+// EnableLocalPath is used to determine whether to deploy the local-path-provisioner.
+func (s *Storage) EnableLocalPath() bool {
+	if s.LocalPath.Enabled == nil {
+		return false
+	}
+	return *s.LocalPath.Enabled
+}
+
+// EnableLonghorn is used to determine whether to deploy longhorn.
+func (s *Storage) EnableLonghorn() bool {
+	if s.Longhorn.Enabled == nil {
+		return false
+	}
+	return *s.Longhorn.Enabled
+}
+
+// Providers returns the enabled storage providers except openebs.
+func (s *Storage) Providers() []string {
+	var providers []string
+	if s.EnableNFS() {
+		providers = append(providers, NFS)
+	}
+	if s.EnableLocalPath() {
+		providers = append(providers, LocalPath)
+	}
+	if s.EnableLonghorn() {
+		providers = append(providers, Longhorn)
+	}
+	return providers
+}
+
+// DefaultProvider returns the provider of the default StorageClass. Openebs is the default if no provider is enabled.
+func (s *Storage) DefaultProvider() string {
+	if s.DefaultStorageClass != "" {
+		return s.DefaultStorageClass
+	}
+	if providers := s.Providers(); len(providers) != 0 {
+		return providers[0]
+	}
+	return OpenEBS
+}
@@ -46,5 +46,6 @@ func NewCmdArtifact() *cobra.Command {
 	cmd.AddCommand(NewCmdArtifactExport())
 	cmd.AddCommand(images.NewCmdArtifactImages())
 	cmd.AddCommand(NewCmdArtifactImport())
+	cmd.AddCommand(NewCmdArtifactVerify())
 	return cmd
 }
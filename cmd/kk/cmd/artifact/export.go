@@ -18,6 +18,7 @@ package artifact
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -34,6 +35,8 @@ type ArtifactExportOptions struct {
 	Output       string
 	CriSocket    string
 	DownloadCmd  string
+	Base         string
+	SignKey      string
 }
 
 func NewArtifactExportOptions() *ArtifactExportOptions {
@@ -71,6 +74,16 @@ func (o *ArtifactExportOptions) Validate(_ []string) error {
 	if o.ManifestFile == "" {
 		return fmt.Errorf("--manifest can not be an empty string")
 	}
+	if o.Base != "" {
+		if _, err := os.Stat(o.Base); err != nil {
+			return fmt.Errorf("the base artifact %s is not accessible: %v", o.Base, err)
+		}
+	}
+	if o.SignKey != "" {
+		if _, err := os.Stat(o.SignKey); err != nil {
+			return fmt.Errorf("the sign key %s is not accessible: %v", o.SignKey, err)
+		}
+	}
 	return nil
 }
 
@@ -80,7 +93,10 @@ func (o *ArtifactExportOptions) Run() error {
 		Output:       o.Output,
 		CriSocket:    o.CriSocket,
 		Debug:        o.CommonOptions.Verbose,
+		ReportFile:   o.CommonOptions.ReportFile,
 		IgnoreErr:    o.CommonOptions.IgnoreErr,
+		Base:         o.Base,
+		SignKey:      o.SignKey,
 	}
 
 	return pipelines.ArtifactExport(arg, o.DownloadCmd)
@@ -91,4 +107,8 @@ func (o *ArtifactExportOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Path to a output path")
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVarP(&o.Base, "base", "", "",
+		"Path to a base artifact, only the binaries, iso files and image layers not present in it are exported as a delta")
+	cmd.Flags().StringVarP(&o.SignKey, "sign-key", "", "",
+		"Path to the ed25519 private key (PKCS #8 PEM) to sign the index of the artifact with")
 }
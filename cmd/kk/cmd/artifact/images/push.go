@@ -37,9 +37,11 @@ import (
 type ArtifactImagesPushOptions struct {
 	CommonOptions *options.CommonOptions
 
-	ImageDirPath   string
-	Artifact       string
-	ClusterCfgFile string
+	ImageDirPath       string
+	Artifact           string
+	ArtifactPublicKey  string
+	SkipArtifactVerify bool
+	ClusterCfgFile     string
 }
 
 func NewArtifactImagesPushOptions() *ArtifactImagesPushOptions {
@@ -90,11 +92,14 @@ func (o *ArtifactImagesPushOptions) Validate(_ []string) error {
 
 func (o *ArtifactImagesPushOptions) Run() error {
 	arg := common.Argument{
-		ImagesDir: o.ImageDirPath,
-		Artifact:  o.Artifact,
-		FilePath:  o.ClusterCfgFile,
-		Debug:     o.CommonOptions.Verbose,
-		IgnoreErr: o.CommonOptions.IgnoreErr,
+		ImagesDir:          o.ImageDirPath,
+		Artifact:           o.Artifact,
+		ArtifactPublicKey:  o.ArtifactPublicKey,
+		SkipArtifactVerify: o.SkipArtifactVerify,
+		FilePath:           o.ClusterCfgFile,
+		Debug:              o.CommonOptions.Verbose,
+		ReportFile:         o.CommonOptions.ReportFile,
+		IgnoreErr:          o.CommonOptions.IgnoreErr,
 	}
 	return runPush(arg)
 }
@@ -102,6 +107,8 @@ func (o *ArtifactImagesPushOptions) Run() error {
 func (o *ArtifactImagesPushOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ImageDirPath, "images-dir", "", "", "Path to a KubeKey artifact images directory")
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().StringVarP(&o.ArtifactPublicKey, "artifact-public-key", "", "", "Path to the ed25519 public key to verify the signature of the artifact")
+	cmd.Flags().BoolVarP(&o.SkipArtifactVerify, "skip-artifact-verify", "", false, "Unarchive the artifact even if it doesn't match its signed index")
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 }
 
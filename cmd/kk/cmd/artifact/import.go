@@ -28,8 +28,10 @@ import (
 )
 
 type ArtifactImportOptions struct {
-	CommonOptions *options.CommonOptions
-	Artifact      string
+	CommonOptions      *options.CommonOptions
+	Artifact           string
+	ArtifactPublicKey  string
+	SkipArtifactVerify bool
 }
 
 func NewArtifactImportOptions() *ArtifactImportOptions {
@@ -57,14 +59,19 @@ func NewCmdArtifactImport() *cobra.Command {
 
 func (o *ArtifactImportOptions) Run() error {
 	arg := common.Argument{
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		Debug:              o.CommonOptions.Verbose,
+		ReportFile:         o.CommonOptions.ReportFile,
+		Artifact:           o.Artifact,
+		ArtifactPublicKey:  o.ArtifactPublicKey,
+		SkipArtifactVerify: o.SkipArtifactVerify,
 	}
 	return artifact.ArtifactImport(arg)
 }
 
 func (o *ArtifactImportOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a artifact gzip")
+	cmd.Flags().StringVarP(&o.ArtifactPublicKey, "artifact-public-key", "", "", "Path to the ed25519 public key to verify the signature of the artifact")
+	cmd.Flags().BoolVarP(&o.SkipArtifactVerify, "skip-artifact-verify", "", false, "Unarchive the artifact even if it doesn't match its signed index")
 }
 
 func (o *ArtifactImportOptions) Validate(_ []string) error {
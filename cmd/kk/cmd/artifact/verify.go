@@ -0,0 +1,80 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/artifact"
+)
+
+type ArtifactVerifyOptions struct {
+	Artifact  string
+	PublicKey string
+}
+
+func NewArtifactVerifyOptions() *ArtifactVerifyOptions {
+	return &ArtifactVerifyOptions{}
+}
+
+// NewCmdArtifactVerify creates a new `kubekey artifact verify` command
+func NewCmdArtifactVerify() *cobra.Command {
+	o := NewArtifactVerifyOptions()
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify a KubeKey offline installation package against its signed index",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *ArtifactVerifyOptions) Run() error {
+	index, err := artifact.VerifyArtifact(o.Artifact, o.PublicKey)
+	if err != nil {
+		return err
+	}
+	if index == nil {
+		return fmt.Errorf("the artifact %s has no index, it may be exported by an older version of KubeKey", o.Artifact)
+	}
+	if o.PublicKey == "" {
+		fmt.Printf("The artifact %s matches its index, %d files are checked. The signature is not verified without --public-key.\n", o.Artifact, len(index.Files))
+		return nil
+	}
+	fmt.Printf("The artifact %s is verified, %d files are checked.\n", o.Artifact, len(index.Files))
+	return nil
+}
+
+func (o *ArtifactVerifyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().StringVarP(&o.PublicKey, "public-key", "", "", "Path to the ed25519 public key (PKIX PEM) to verify the signature of the artifact")
+}
+
+func (o *ArtifactVerifyOptions) Validate(_ []string) error {
+	if o.Artifact == "" {
+		return errors.New("artifact path can not be empty")
+	}
+	return nil
+}
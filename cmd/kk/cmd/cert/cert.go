@@ -44,5 +44,6 @@ func NewCmdCerts() *cobra.Command {
 
 	cmd.AddCommand(NewCmdCertList())
 	cmd.AddCommand(NewCmdCertRenew())
+	cmd.AddCommand(NewCmdCertRotateCA())
 	return cmd
 }
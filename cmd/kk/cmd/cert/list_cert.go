@@ -17,10 +17,13 @@ limitations under the License.
 package cert
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/spf13/cobra"
 
 	"github.com/kubesphere/kubekey/cmd/kk/cmd/options"
 	"github.com/kubesphere/kubekey/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/certs"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/pipelines"
 )
@@ -28,6 +31,9 @@ import (
 type CertListOptions struct {
 	CommonOptions  *options.CommonOptions
 	ClusterCfgFile string
+	Output         string
+	TextFile       string
+	WarnDays       int
 }
 
 func NewCertListOptions() *CertListOptions {
@@ -43,6 +49,7 @@ func NewCmdCertList() *cobra.Command {
 		Use:   "check-expiration",
 		Short: "Check certificates expiration for a Kubernetes cluster",
 		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate())
 			util.CheckErr(o.Run())
 		},
 	}
@@ -54,12 +61,31 @@ func NewCmdCertList() *cobra.Command {
 
 func (o *CertListOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
+		FilePath:       o.ClusterCfgFile,
+		Debug:          o.CommonOptions.Verbose,
+		ReportFile:     o.CommonOptions.ReportFile,
+		HostKeyPolicy:  o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile: o.CommonOptions.KnownHostsFile,
+		Output:         o.Output,
+		TextFile:       o.TextFile,
+		WarnDays:       o.WarnDays,
 	}
 	return pipelines.CheckCerts(arg)
 }
 
+func (o *CertListOptions) Validate() error {
+	if !certs.ValidOutput(o.Output) {
+		return errors.Errorf("invalid output format: %s, available options are 'table', 'json', 'yaml' and 'prometheus'", o.Output)
+	}
+	if o.WarnDays < 0 {
+		return errors.New("--warn-days must not be negative")
+	}
+	return nil
+}
+
 func (o *CertListOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Output format; available options are 'table', 'json', 'yaml' and 'prometheus'")
+	cmd.Flags().StringVar(&o.TextFile, "textfile", "", "Path to a file to write the expiration timestamps in the Prometheus text format, e.g. for the textfile collector of node_exporter")
+	cmd.Flags().IntVar(&o.WarnDays, "warn-days", 0, "Exit with a non-zero code if any certificate expires within the given days, 0 disables the check")
 }
@@ -54,8 +54,11 @@ func NewCmdCertRenew() *cobra.Command {
 
 func (o *CertRenewOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
+		FilePath:       o.ClusterCfgFile,
+		Debug:          o.CommonOptions.Verbose,
+		ReportFile:     o.CommonOptions.ReportFile,
+		HostKeyPolicy:  o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile: o.CommonOptions.KnownHostsFile,
 	}
 	return pipelines.RenewCerts(arg)
 }
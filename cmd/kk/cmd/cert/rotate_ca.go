@@ -0,0 +1,73 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cert
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/pipelines"
+)
+
+type CertRotateCAOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+}
+
+func NewCertRotateCAOptions() *CertRotateCAOptions {
+	return &CertRotateCAOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdCertRotateCA creates a new cert rotate-ca command
+func NewCmdCertRotateCA() *cobra.Command {
+	o := NewCertRotateCAOptions()
+	cmd := &cobra.Command{
+		Use:   "rotate-ca",
+		Short: "rotate the cluster CAs and the etcd CA",
+		Long: `Rotate the Kubernetes CA, the front-proxy CA and the etcd CA of a cluster.
+
+The new CAs are trusted alongside the old ones first, then all the certificates are re-issued
+with the new CAs, and finally the old CAs are dropped. Each phase restarts etcd, the control-plane
+and the kubelets one node at a time. The rotation can be run again if it's interrupted.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *CertRotateCAOptions) Run() error {
+	arg := common.Argument{
+		FilePath:       o.ClusterCfgFile,
+		Debug:          o.CommonOptions.Verbose,
+		ReportFile:     o.CommonOptions.ReportFile,
+		HostKeyPolicy:  o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile: o.CommonOptions.KnownHostsFile,
+	}
+	return pipelines.RotateCA(arg)
+}
+
+func (o *CertRotateCAOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+}
@@ -33,18 +33,23 @@ import (
 type CreateClusterOptions struct {
 	CommonOptions *options.CommonOptions
 
-	ClusterCfgFile      string
-	Kubernetes          string
-	EnableKubeSphere    bool
-	KubeSphere          string
-	LocalStorage        bool
-	SkipPullImages      bool
-	SkipPushImages      bool
-	SecurityEnhancement bool
-	ContainerManager    string
-	DownloadCmd         string
-	Artifact            string
-	InstallPackages     bool
+	ClusterCfgFile        string
+	Kubernetes            string
+	EnableKubeSphere      bool
+	KubeSphere            string
+	LocalStorage          bool
+	SkipPullImages        bool
+	SkipPushImages        bool
+	SecurityEnhancement   bool
+	ContainerManager      string
+	DownloadCmd           string
+	Artifact              string
+	ArtifactPublicKey     string
+	SkipArtifactVerify    bool
+	InstallPackages       bool
+	Resume                bool
+	DryRun                bool
+	IgnorePreflightErrors []string
 
 	localStorageChanged bool
 }
@@ -108,20 +113,28 @@ func (o *CreateClusterOptions) Validate(_ *cobra.Command, _ []string) error {
 
 func (o *CreateClusterOptions) Run() error {
 	arg := common.Argument{
-		FilePath:            o.ClusterCfgFile,
-		KubernetesVersion:   o.Kubernetes,
-		KsEnable:            o.EnableKubeSphere,
-		KsVersion:           o.KubeSphere,
-		SkipPullImages:      o.SkipPullImages,
-		SKipPushImages:      o.SkipPushImages,
-		SecurityEnhancement: o.SecurityEnhancement,
-		Debug:               o.CommonOptions.Verbose,
-		IgnoreErr:           o.CommonOptions.IgnoreErr,
-		SkipConfirmCheck:    o.CommonOptions.SkipConfirmCheck,
-		ContainerManager:    o.ContainerManager,
-		Artifact:            o.Artifact,
-		InstallPackages:     o.InstallPackages,
-		Namespace:           o.CommonOptions.Namespace,
+		FilePath:              o.ClusterCfgFile,
+		KubernetesVersion:     o.Kubernetes,
+		KsEnable:              o.EnableKubeSphere,
+		KsVersion:             o.KubeSphere,
+		SkipPullImages:        o.SkipPullImages,
+		SKipPushImages:        o.SkipPushImages,
+		SecurityEnhancement:   o.SecurityEnhancement,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		IgnoreErr:             o.CommonOptions.IgnoreErr,
+		SkipConfirmCheck:      o.CommonOptions.SkipConfirmCheck,
+		ContainerManager:      o.ContainerManager,
+		Artifact:              o.Artifact,
+		ArtifactPublicKey:     o.ArtifactPublicKey,
+		SkipArtifactVerify:    o.SkipArtifactVerify,
+		InstallPackages:       o.InstallPackages,
+		Namespace:             o.CommonOptions.Namespace,
+		Resume:                o.Resume,
+		DryRun:                o.DryRun,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 
 	if o.localStorageChanged {
@@ -144,7 +157,12 @@ func (o *CreateClusterOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().StringVarP(&o.ArtifactPublicKey, "artifact-public-key", "", "", "Path to the ed25519 public key to verify the signature of the artifact")
+	cmd.Flags().BoolVarP(&o.SkipArtifactVerify, "skip-artifact-verify", "", false, "Unarchive the artifact even if it doesn't match its signed index")
 	cmd.Flags().BoolVarP(&o.InstallPackages, "with-packages", "", false, "install operation system packages by artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Skip the modules completed by the previous failed run with the same configuration")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the plan of tasks per host and the rendered configurations without executing any remote command")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
 
 func completionSetting(cmd *cobra.Command) (err error) {
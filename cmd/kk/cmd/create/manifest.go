@@ -38,6 +38,8 @@ type CreateManifestOptions struct {
 	Name       string
 	KubeConfig string
 	FileName   string
+	FromConfig string
+	DetectOS   bool
 }
 
 func NewCreateManifestOptions() *CreateManifestOptions {
@@ -82,6 +84,16 @@ func (o *CreateManifestOptions) Complete(cmd *cobra.Command, args []string) erro
 }
 
 func (o *CreateManifestOptions) Run() error {
+	if o.FromConfig != "" {
+		arg := common.Argument{
+			FilePath:       o.FromConfig,
+			Debug:          o.CommonOptions.Verbose,
+			HostKeyPolicy:  o.CommonOptions.HostKeyPolicy,
+			KnownHostsFile: o.CommonOptions.KnownHostsFile,
+		}
+		return artifact.CreateManifestFromConfig(arg, o.Name, o.FileName, o.DetectOS)
+	}
+
 	arg := common.Argument{
 		FilePath:   o.FileName,
 		KubeConfig: o.KubeConfig,
@@ -93,4 +105,6 @@ func (o *CreateManifestOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Name, "name", "", "sample", "Specify a name of manifest object")
 	cmd.Flags().StringVarP(&o.FileName, "filename", "f", "", "Specify a manifest file path")
 	cmd.Flags().StringVar(&o.KubeConfig, "kubeconfig", "", "Specify a kubeconfig file")
+	cmd.Flags().StringVar(&o.FromConfig, "from-config", "", "Specify a cluster configuration file to create the manifest from, instead of a running cluster")
+	cmd.Flags().BoolVar(&o.DetectOS, "detect-os", false, "Read the operating systems from the hosts in the cluster configuration file over SSH, used with --from-config")
 }
@@ -29,10 +29,11 @@ import (
 )
 
 type CreateBinaryOptions struct {
-	CommonOptions  *options.CommonOptions
-	ClusterCfgFile string
-	Kubernetes     string
-	DownloadCmd    string
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	Kubernetes            string
+	DownloadCmd           string
+	IgnorePreflightErrors []string
 }
 
 func NewCreateBinaryOptions() *CreateBinaryOptions {
@@ -62,9 +63,13 @@ func NewCmdCreateBinary() *cobra.Command {
 
 func (o *CreateBinaryOptions) Run() error {
 	arg := common.Argument{
-		FilePath:          o.ClusterCfgFile,
-		KubernetesVersion: o.Kubernetes,
-		Debug:             o.CommonOptions.Verbose,
+		FilePath:              o.ClusterCfgFile,
+		KubernetesVersion:     o.Kubernetes,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 	return binary.CreateBinary(arg, o.DownloadCmd)
 }
@@ -75,6 +80,7 @@ func (o *CreateBinaryOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
 
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
 
 func k8sCompletionSetting(cmd *cobra.Command) (err error) {
@@ -28,10 +28,11 @@ import (
 )
 
 type CreateConfigureKubernetesOptions struct {
-	CommonOptions  *options.CommonOptions
-	ClusterCfgFile string
-	Kubernetes     string
-	LocalStorage   bool
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	Kubernetes            string
+	LocalStorage          bool
+	IgnorePreflightErrors []string
 
 	localStorageChanged bool
 }
@@ -71,10 +72,14 @@ func NewCmdCreateConfigureKubernetes() *cobra.Command {
 
 func (o *CreateConfigureKubernetesOptions) Run() error {
 	arg := common.Argument{
-		FilePath:          o.ClusterCfgFile,
-		KubernetesVersion: o.Kubernetes,
-		Debug:             o.CommonOptions.Verbose,
-		Namespace:         o.CommonOptions.Namespace,
+		FilePath:              o.ClusterCfgFile,
+		KubernetesVersion:     o.Kubernetes,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		Namespace:             o.CommonOptions.Namespace,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 
 	if o.localStorageChanged {
@@ -89,4 +94,5 @@ func (o *CreateConfigureKubernetesOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().StringVarP(&o.Kubernetes, "with-kubernetes", "", "", "Specify a supported version of kubernetes")
 	cmd.Flags().BoolVarP(&o.LocalStorage, "with-local-storage", "", false, "Deploy a local PV provisioner")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
@@ -28,10 +28,11 @@ import (
 )
 
 type CreateImagesOptions struct {
-	CommonOptions    *options.CommonOptions
-	ClusterCfgFile   string
-	Kubernetes       string
-	ContainerManager string
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	Kubernetes            string
+	ContainerManager      string
+	IgnorePreflightErrors []string
 }
 
 func NewCreateImagesOptions() *CreateImagesOptions {
@@ -71,10 +72,14 @@ func (o *CreateImagesOptions) Validate(_ *cobra.Command, _ []string) error {
 
 func (o *CreateImagesOptions) Run() error {
 	arg := common.Argument{
-		FilePath:          o.ClusterCfgFile,
-		KubernetesVersion: o.Kubernetes,
-		ContainerManager:  o.ContainerManager,
-		Debug:             o.CommonOptions.Verbose,
+		FilePath:              o.ClusterCfgFile,
+		KubernetesVersion:     o.Kubernetes,
+		ContainerManager:      o.ContainerManager,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 	return images.CreateImages(arg)
 }
@@ -83,4 +88,5 @@ func (o *CreateImagesOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().StringVarP(&o.Kubernetes, "with-kubernetes", "", "", "Specify a supported version of kubernetes")
 	cmd.Flags().StringVarP(&o.ContainerManager, "container-manager", "", "docker", "Container runtime: docker, crio, containerd and isula.")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
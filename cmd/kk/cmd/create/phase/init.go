@@ -28,9 +28,10 @@ import (
 )
 
 type CreateInitClusterOptions struct {
-	CommonOptions  *options.CommonOptions
-	ClusterCfgFile string
-	Kubernetes     string
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	Kubernetes            string
+	IgnorePreflightErrors []string
 }
 
 func NewCreateInitClusterOptions() *CreateInitClusterOptions {
@@ -60,10 +61,14 @@ func NewCmdCreateInitCluster() *cobra.Command {
 
 func (o *CreateInitClusterOptions) Run() error {
 	arg := common.Argument{
-		FilePath:          o.ClusterCfgFile,
-		KubernetesVersion: o.Kubernetes,
-		Debug:             o.CommonOptions.Verbose,
-		Namespace:         o.CommonOptions.Namespace,
+		FilePath:              o.ClusterCfgFile,
+		KubernetesVersion:     o.Kubernetes,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		Namespace:             o.CommonOptions.Namespace,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 
 	return kubernetes.CreateInitCluster(arg)
@@ -72,4 +77,5 @@ func (o *CreateInitClusterOptions) Run() error {
 func (o *CreateInitClusterOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().StringVarP(&o.Kubernetes, "with-kubernetes", "", "", "Specify a supported version of kubernetes")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
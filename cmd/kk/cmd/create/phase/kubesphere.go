@@ -30,10 +30,11 @@ import (
 )
 
 type CreateKubeSphereOptions struct {
-	CommonOptions    *options.CommonOptions
-	ClusterCfgFile   string
-	EnableKubeSphere bool
-	KubeSphere       string
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	EnableKubeSphere      bool
+	KubeSphere            string
+	IgnorePreflightErrors []string
 }
 
 func NewCreateKubeSphereOptions() *CreateKubeSphereOptions {
@@ -75,11 +76,15 @@ func (o *CreateKubeSphereOptions) Complete(cmd *cobra.Command, args []string) er
 
 func (o *CreateKubeSphereOptions) Run() error {
 	arg := common.Argument{
-		FilePath:         o.ClusterCfgFile,
-		KsEnable:         o.EnableKubeSphere,
-		KsVersion:        o.KubeSphere,
-		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
-		Debug:            o.CommonOptions.Verbose,
+		FilePath:              o.ClusterCfgFile,
+		KsEnable:              o.EnableKubeSphere,
+		KsVersion:             o.KubeSphere,
+		SkipConfirmCheck:      o.CommonOptions.SkipConfirmCheck,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 	return alpha.CreateKubeSphere(arg)
 }
@@ -87,6 +92,7 @@ func (o *CreateKubeSphereOptions) Run() error {
 func (o *CreateKubeSphereOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().BoolVarP(&o.EnableKubeSphere, "with-kubesphere", "", false, fmt.Sprintf("Deploy a specific version of kubesphere (default %s)", kubesphere.Latest().Version))
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
 
 func ksCompletionSetting(cmd *cobra.Command) (err error) {
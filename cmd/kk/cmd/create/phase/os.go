@@ -26,9 +26,10 @@ import (
 )
 
 type ConfigOSOptions struct {
-	CommonOptions   *options.CommonOptions
-	ClusterCfgFile  string
-	InstallPackages bool
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	InstallPackages       bool
+	IgnorePreflightErrors []string
 }
 
 func NewConfigOSOptions() *ConfigOSOptions {
@@ -55,9 +56,13 @@ func NewCmdConfigOS() *cobra.Command {
 
 func (o *ConfigOSOptions) Run() error {
 	arg := common.Argument{
-		FilePath:        o.ClusterCfgFile,
-		Debug:           o.CommonOptions.Verbose,
-		InstallPackages: o.InstallPackages,
+		FilePath:              o.ClusterCfgFile,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		InstallPackages:       o.InstallPackages,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 	return os.ConfigOS(arg)
 }
@@ -65,4 +70,5 @@ func (o *ConfigOSOptions) Run() error {
 func (o *ConfigOSOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().BoolVarP(&o.InstallPackages, "with-packages", "", false, "install operation system packages by artifact")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
@@ -66,6 +66,9 @@ func (o *MigrateCriOptions) Run() error {
 	arg := common.Argument{
 		FilePath:          o.ClusterCfgFile,
 		Debug:             o.CommonOptions.Verbose,
+		ReportFile:        o.CommonOptions.ReportFile,
+		HostKeyPolicy:     o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:    o.CommonOptions.KnownHostsFile,
 		KubernetesVersion: o.Kubernetes,
 		Type:              o.Type,
 		Role:              o.Role,
@@ -58,6 +58,9 @@ func (o *DeleteClusterOptions) Run() error {
 	arg := common.Argument{
 		FilePath:          o.ClusterCfgFile,
 		Debug:             o.CommonOptions.Verbose,
+		ReportFile:        o.CommonOptions.ReportFile,
+		HostKeyPolicy:     o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:    o.CommonOptions.KnownHostsFile,
 		KubernetesVersion: o.Kubernetes,
 		DeleteCRI:         o.DeleteCRI,
 	}
@@ -31,6 +31,7 @@ import (
 type DeleteNodeOptions struct {
 	CommonOptions  *options.CommonOptions
 	ClusterCfgFile string
+	DryRun         bool
 	nodeName       string
 }
 
@@ -72,14 +73,18 @@ func (o *DeleteNodeOptions) Validate() error {
 
 func (o *DeleteNodeOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		NodeName: o.nodeName,
+		FilePath:       o.ClusterCfgFile,
+		Debug:          o.CommonOptions.Verbose,
+		ReportFile:     o.CommonOptions.ReportFile,
+		HostKeyPolicy:  o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile: o.CommonOptions.KnownHostsFile,
+		NodeName:       o.nodeName,
+		DryRun:         o.DryRun,
 	}
 	return pipelines.DeleteNode(arg)
 }
 
 func (o *DeleteNodeOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
-
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the plan of tasks per host and the rendered configurations without executing any remote command")
 }
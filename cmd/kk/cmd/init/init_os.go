@@ -26,9 +26,11 @@ import (
 )
 
 type InitOsOptions struct {
-	CommonOptions  *options.CommonOptions
-	ClusterCfgFile string
-	Artifact       string
+	CommonOptions      *options.CommonOptions
+	ClusterCfgFile     string
+	Artifact           string
+	ArtifactPublicKey  string
+	SkipArtifactVerify bool
 }
 
 func NewInitOsOptions() *InitOsOptions {
@@ -55,9 +57,14 @@ func NewCmdInitOs() *cobra.Command {
 
 func (o *InitOsOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		FilePath:           o.ClusterCfgFile,
+		Debug:              o.CommonOptions.Verbose,
+		ReportFile:         o.CommonOptions.ReportFile,
+		HostKeyPolicy:      o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:     o.CommonOptions.KnownHostsFile,
+		Artifact:           o.Artifact,
+		ArtifactPublicKey:  o.ArtifactPublicKey,
+		SkipArtifactVerify: o.SkipArtifactVerify,
 	}
 	return pipelines.InitDependencies(arg)
 }
@@ -65,4 +72,6 @@ func (o *InitOsOptions) Run() error {
 func (o *InitOsOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().StringVarP(&o.ArtifactPublicKey, "artifact-public-key", "", "", "Path to the ed25519 public key to verify the signature of the artifact")
+	cmd.Flags().BoolVarP(&o.SkipArtifactVerify, "skip-artifact-verify", "", false, "Unarchive the artifact even if it doesn't match its signed index")
 }
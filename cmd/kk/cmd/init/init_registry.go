@@ -26,10 +26,12 @@ import (
 )
 
 type InitRegistryOptions struct {
-	CommonOptions  *options.CommonOptions
-	ClusterCfgFile string
-	DownloadCmd    string
-	Artifact       string
+	CommonOptions      *options.CommonOptions
+	ClusterCfgFile     string
+	DownloadCmd        string
+	Artifact           string
+	ArtifactPublicKey  string
+	SkipArtifactVerify bool
 }
 
 func NewInitRegistryOptions() *InitRegistryOptions {
@@ -61,9 +63,14 @@ func (o *InitRegistryOptions) Complete(_ *cobra.Command, _ []string) error {
 
 func (o *InitRegistryOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		FilePath:           o.ClusterCfgFile,
+		Debug:              o.CommonOptions.Verbose,
+		ReportFile:         o.CommonOptions.ReportFile,
+		HostKeyPolicy:      o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:     o.CommonOptions.KnownHostsFile,
+		Artifact:           o.Artifact,
+		ArtifactPublicKey:  o.ArtifactPublicKey,
+		SkipArtifactVerify: o.SkipArtifactVerify,
 	}
 	return pipelines.InitRegistry(arg, o.DownloadCmd)
 }
@@ -73,4 +80,6 @@ func (o *InitRegistryOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().StringVarP(&o.ArtifactPublicKey, "artifact-public-key", "", "", "Path to the ed25519 public key to verify the signature of the artifact")
+	cmd.Flags().BoolVarP(&o.SkipArtifactVerify, "skip-artifact-verify", "", false, "Unarchive the artifact even if it doesn't match its signed index")
 }
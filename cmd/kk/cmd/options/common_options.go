@@ -25,6 +25,9 @@ type CommonOptions struct {
 	SkipConfirmCheck bool
 	IgnoreErr        bool
 	Namespace        string
+	ReportFile       string
+	HostKeyPolicy    string
+	KnownHostsFile   string
 }
 
 func NewCommonOptions() *CommonOptions {
@@ -36,4 +39,7 @@ func (o *CommonOptions) AddCommonFlag(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&o.SkipConfirmCheck, "yes", "y", false, "Skip confirm check")
 	cmd.Flags().BoolVar(&o.IgnoreErr, "ignore-err", false, "Ignore the error message, remove the host which reported error and force to continue")
 	cmd.Flags().StringVar(&o.Namespace, "namespace", "kubekey-system", "KubeKey namespace to use")
+	cmd.Flags().StringVar(&o.ReportFile, "report-file", "", "Path to write the execution report to. A JUnit XML report is written if the file ends with .xml, otherwise a JSON report")
+	cmd.Flags().StringVar(&o.HostKeyPolicy, "host-key-policy", "tofu", "The policy to verify the SSH host keys: strict (verify against the known hosts file), tofu (trust and record the key of a new host) or insecure (accept any key)")
+	cmd.Flags().StringVar(&o.KnownHostsFile, "known-hosts", "", "Path to the known hosts file, defaults to ~/.ssh/known_hosts in strict mode and ./kubekey/known_hosts in tofu mode")
 }
@@ -0,0 +1,72 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package precheck
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/pipelines"
+)
+
+type PreCheckOptions struct {
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	IgnorePreflightErrors []string
+}
+
+func NewPreCheckOptions() *PreCheckOptions {
+	return &PreCheckOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdPreCheck creates a new precheck command
+func NewCmdPreCheck() *cobra.Command {
+	o := NewPreCheckOptions()
+	cmd := &cobra.Command{
+		Use:   "precheck",
+		Short: "Run the pre-flight checks on the nodes of a cluster without changing them",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *PreCheckOptions) Run() error {
+	arg := common.Argument{
+		FilePath:              o.ClusterCfgFile,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		IgnoreErr:             o.CommonOptions.IgnoreErr,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
+	}
+	return pipelines.PreCheck(arg)
+}
+
+func (o *PreCheckOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
+}
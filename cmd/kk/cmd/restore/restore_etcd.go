@@ -0,0 +1,72 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package restore
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/pipelines"
+)
+
+type RestoreETCDOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	Snapshot       string
+}
+
+func NewRestoreETCDOptions() *RestoreETCDOptions {
+	return &RestoreETCDOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdRestoreETCD creates a new restore etcd command
+func NewCmdRestoreETCD() *cobra.Command {
+	o := NewRestoreETCDOptions()
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Restore the etcd cluster from a snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *RestoreETCDOptions) Run() error {
+	arg := common.Argument{
+		FilePath:         o.ClusterCfgFile,
+		Debug:            o.CommonOptions.Verbose,
+		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
+		ReportFile:       o.CommonOptions.ReportFile,
+		HostKeyPolicy:    o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:   o.CommonOptions.KnownHostsFile,
+		EtcdSnapshot:     o.Snapshot,
+	}
+	return pipelines.RestoreETCD(arg)
+}
+
+func (o *RestoreETCDOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVarP(&o.Snapshot, "snapshot", "", "", "Path to the etcd snapshot, a local file or a file which exists on every etcd node")
+}
@@ -36,6 +36,8 @@ import (
 	initOs "github.com/kubesphere/kubekey/cmd/kk/cmd/init"
 	"github.com/kubesphere/kubekey/cmd/kk/cmd/options"
 	"github.com/kubesphere/kubekey/cmd/kk/cmd/plugin"
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/precheck"
+	"github.com/kubesphere/kubekey/cmd/kk/cmd/restore"
 	"github.com/kubesphere/kubekey/cmd/kk/cmd/upgrade"
 	"github.com/kubesphere/kubekey/cmd/kk/cmd/version"
 )
@@ -107,11 +109,13 @@ func NewKubeKeyCommand(o KubeKeyOptions) *cobra.Command {
 
 	cmds.AddCommand(initOs.NewCmdInit())
 
+	cmds.AddCommand(precheck.NewCmdPreCheck())
 	cmds.AddCommand(create.NewCmdCreate())
 	cmds.AddCommand(delete.NewCmdDelete())
 	cmds.AddCommand(add.NewCmdAdd())
 	cmds.AddCommand(upgrade.NewCmdUpgrade())
 	cmds.AddCommand(cert.NewCmdCerts())
+	cmds.AddCommand(restore.NewCmdRestore())
 	cmds.AddCommand(artifact.NewCmdArtifact())
 
 	cmds.AddCommand(plugin.NewCmdPlugin(o.IOStreams))
@@ -66,6 +66,9 @@ func (o *UpgradeBinaryOptions) Run() error {
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
 		Debug:             o.CommonOptions.Verbose,
+		ReportFile:        o.CommonOptions.ReportFile,
+		HostKeyPolicy:     o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:    o.CommonOptions.KnownHostsFile,
 	}
 	return binary.UpgradeBinary(arg, o.DownloadCmd)
 }
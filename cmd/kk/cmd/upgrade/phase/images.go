@@ -64,6 +64,9 @@ func (o *UpgradeImagesOptions) Run() error {
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
 		Debug:             o.CommonOptions.Verbose,
+		ReportFile:        o.CommonOptions.ReportFile,
+		HostKeyPolicy:     o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:    o.CommonOptions.KnownHostsFile,
 	}
 	return images.UpgradeImages(arg)
 }
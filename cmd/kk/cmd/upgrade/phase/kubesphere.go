@@ -81,6 +81,9 @@ func (o *UpgradeKubeSphereOptions) Run() error {
 		KsVersion:        o.KubeSphere,
 		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
 		Debug:            o.CommonOptions.Verbose,
+		ReportFile:       o.CommonOptions.ReportFile,
+		HostKeyPolicy:    o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:   o.CommonOptions.KnownHostsFile,
 	}
 	return alpha.UpgradeKubeSphere(arg)
 }
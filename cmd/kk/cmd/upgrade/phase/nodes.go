@@ -63,6 +63,9 @@ func (o *UpgradeNodesOptions) Run() error {
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
 		Debug:             o.CommonOptions.Verbose,
+		ReportFile:        o.CommonOptions.ReportFile,
+		HostKeyPolicy:     o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:    o.CommonOptions.KnownHostsFile,
 	}
 	return nodes.UpgradeNodes(arg)
 }
@@ -33,14 +33,21 @@ import (
 )
 
 type UpgradeOptions struct {
-	CommonOptions    *options.CommonOptions
-	ClusterCfgFile   string
-	Kubernetes       string
-	EnableKubeSphere bool
-	KubeSphere       string
-	SkipPullImages   bool
-	DownloadCmd      string
-	Artifact         string
+	CommonOptions         *options.CommonOptions
+	ClusterCfgFile        string
+	Kubernetes            string
+	EnableKubeSphere      bool
+	KubeSphere            string
+	SkipPullImages        bool
+	DownloadCmd           string
+	Artifact              string
+	ArtifactPublicKey     string
+	SkipArtifactVerify    bool
+	DryRun                bool
+	MaxUnavailable        int
+	DrainTimeout          time.Duration
+	SkipDrain             bool
+	IgnorePreflightErrors []string
 }
 
 func NewUpgradeOptions() *UpgradeOptions {
@@ -83,14 +90,24 @@ func (o *UpgradeOptions) Complete(cmd *cobra.Command, args []string) error {
 
 func (o *UpgradeOptions) Run() error {
 	arg := common.Argument{
-		FilePath:          o.ClusterCfgFile,
-		KubernetesVersion: o.Kubernetes,
-		KsEnable:          o.EnableKubeSphere,
-		KsVersion:         o.KubeSphere,
-		SkipPullImages:    o.SkipPullImages,
-		Debug:             o.CommonOptions.Verbose,
-		SkipConfirmCheck:  o.CommonOptions.SkipConfirmCheck,
-		Artifact:          o.Artifact,
+		FilePath:              o.ClusterCfgFile,
+		KubernetesVersion:     o.Kubernetes,
+		KsEnable:              o.EnableKubeSphere,
+		KsVersion:             o.KubeSphere,
+		SkipPullImages:        o.SkipPullImages,
+		Debug:                 o.CommonOptions.Verbose,
+		ReportFile:            o.CommonOptions.ReportFile,
+		HostKeyPolicy:         o.CommonOptions.HostKeyPolicy,
+		KnownHostsFile:        o.CommonOptions.KnownHostsFile,
+		SkipConfirmCheck:      o.CommonOptions.SkipConfirmCheck,
+		Artifact:              o.Artifact,
+		ArtifactPublicKey:     o.ArtifactPublicKey,
+		SkipArtifactVerify:    o.SkipArtifactVerify,
+		DryRun:                o.DryRun,
+		MaxUnavailable:        o.MaxUnavailable,
+		DrainTimeout:          o.DrainTimeout,
+		SkipDrain:             o.SkipDrain,
+		IgnorePreflightErrors: o.IgnorePreflightErrors,
 	}
 	return pipelines.UpgradeCluster(arg, o.DownloadCmd)
 }
@@ -103,6 +120,13 @@ func (o *UpgradeOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().StringVarP(&o.ArtifactPublicKey, "artifact-public-key", "", "", "Path to the ed25519 public key to verify the signature of the artifact")
+	cmd.Flags().BoolVarP(&o.SkipArtifactVerify, "skip-artifact-verify", "", false, "Unarchive the artifact even if it doesn't match its signed index")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the plan of tasks per host and the rendered configurations without executing any remote command")
+	cmd.Flags().IntVarP(&o.MaxUnavailable, "max-unavailable", "", 1, "The max number of worker nodes which are upgraded at the same time")
+	cmd.Flags().DurationVarP(&o.DrainTimeout, "drain-timeout", "", 5*time.Minute, "The time to wait for the pods of a node to be evicted before giving up the upgrade")
+	cmd.Flags().BoolVarP(&o.SkipDrain, "skip-drain", "", false, "Upgrade the nodes without draining them")
+	cmd.Flags().StringSliceVarP(&o.IgnorePreflightErrors, "ignore-preflight-errors", "", nil, "A list of pre-flight checks whose failures are shown as warnings, e.g. 'Swap,Ports'. Value 'all' ignores failures from all checks.")
 }
 
 func completionSetting(cmd *cobra.Command) (err error) {
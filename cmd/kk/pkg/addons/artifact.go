@@ -0,0 +1,270 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	helmLoader "helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+	coreutil "github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+// The dirs of the addons in the artifact. They are relative to the work dir after the artifact is unarchived.
+const (
+	chartsDir = "addons/charts"
+	yamlDir   = "addons/yaml"
+)
+
+// chartFile returns the path of the packaged chart in the artifact, which is named by the chart name and version.
+func chartFile(root string, c kubekeyapiv1alpha2.Chart) string {
+	version := c.Version
+	if version == "" {
+		version = "latest"
+	}
+	return filepath.Join(root, chartsDir, fmt.Sprintf("%s-%s.tgz", path.Base(c.Name), version))
+}
+
+// yamlFile returns the path of the remote yaml in the artifact, the hash of the url avoids the conflicts of the file names.
+func yamlFile(root, rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	name := "addon.yaml"
+	if u, err := url.Parse(rawURL); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+		name = path.Base(u.Path)
+	}
+	return filepath.Join(root, yamlDir, fmt.Sprintf("%x-%s", sum[:4], name))
+}
+
+func isRemote(p string) bool {
+	u, err := url.Parse(p)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// resolveFromArtifact returns a copy of the addon whose chart, values file and yaml refer to the files
+// in the unarchived artifact under the root if they exist there.
+func resolveFromArtifact(root string, addon kubekeyapiv1alpha2.Addon) kubekeyapiv1alpha2.Addon {
+	resolved := addon
+	c := addon.Sources.Chart
+	if c.Name != "" {
+		if p := chartFile(root, c); coreutil.IsExist(p) {
+			logger.Log.Infof("Use the chart %s of the addon %s in the artifact", p, addon.Name)
+			resolved.Sources.Chart.Repo = ""
+			resolved.Sources.Chart.Version = ""
+			resolved.Sources.Chart.Path = filepath.Dir(p)
+			resolved.Sources.Chart.Name = filepath.Base(p)
+		}
+	}
+	if isRemote(c.ValuesFile) {
+		if p := yamlFile(root, c.ValuesFile); coreutil.IsExist(p) {
+			resolved.Sources.Chart.ValuesFile = p
+		}
+	}
+
+	resolved.Sources.Yaml.Path = make([]string, 0, len(addon.Sources.Yaml.Path))
+	for _, y := range addon.Sources.Yaml.Path {
+		if p := yamlFile(root, y); isRemote(y) && coreutil.IsExist(p) {
+			logger.Log.Infof("Use the yaml %s of the addon %s in the artifact", p, addon.Name)
+			y = p
+		}
+		resolved.Sources.Yaml.Path = append(resolved.Sources.Yaml.Path, y)
+	}
+	return resolved
+}
+
+// saveChart packages the chart of the addon into the artifact, and returns the images in the rendered chart.
+func saveChart(root string, addon kubekeyapiv1alpha2.Addon) ([]string, error) {
+	settings := cli.New()
+	c := addon.Sources.Chart
+
+	name := c.Name
+	if c.Repo == "" && c.Path != "" {
+		name = filepath.Join(c.Path, c.Name)
+	}
+	pathOptions := action.ChartPathOptions{RepoURL: c.Repo, Version: c.Version}
+	chartPath, err := pathOptions.LocateChart(name, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "locate the chart %s of the addon %s failed", c.Name, addon.Name)
+	}
+	ch, err := helmLoader.Load(chartPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load the chart %s failed", chartPath)
+	}
+
+	dst := chartFile(root, c)
+	if err := coreutil.Mkdir(filepath.Dir(dst)); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "mkdir %s failed", filepath.Dir(dst))
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dst), ".chart-")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	saved, err := chartutil.Save(ch, tmpDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "package the chart %s failed", c.Name)
+	}
+	if err := os.Rename(saved, dst); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifest, err := renderChart(addon, ch, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "render the chart %s of the addon %s failed", c.Name, addon.Name)
+	}
+	images, err := imagesInManifests([]byte(manifest))
+	if err != nil {
+		return nil, errors.Wrapf(err, "get the images of the chart %s failed", c.Name)
+	}
+	return images, nil
+}
+
+// renderChart renders the chart with the values of the addon without connecting to the cluster.
+func renderChart(addon kubekeyapiv1alpha2.Addon, ch *chart.Chart, settings *cli.EnvSettings) (string, error) {
+	valueOpts := &values.Options{
+		Values: addon.Sources.Chart.Values,
+	}
+	if addon.Sources.Chart.ValuesFile != "" {
+		valueOpts.ValueFiles = []string{addon.Sources.Chart.ValuesFile}
+	}
+	vals, err := valueOpts.MergeValues(getter.All(settings))
+	if err != nil {
+		return "", err
+	}
+
+	namespace := addon.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	client := action.NewInstall(&action.Configuration{Log: debug})
+	client.DryRun = true
+	client.ClientOnly = true
+	client.Replace = true
+	client.IncludeCRDs = true
+	client.ReleaseName = addon.Name
+	client.Namespace = namespace
+	rel, err := client.Run(ch, vals)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		buf.WriteString("\n---\n")
+		buf.WriteString(hook.Manifest)
+	}
+	return buf.String(), nil
+}
+
+// saveRemoteFile downloads the remote yaml or values file into the artifact, and returns its content.
+func saveRemoteFile(root, rawURL string, downloadCommand func(path, url string) string) ([]byte, error) {
+	dst := yamlFile(root, rawURL)
+	if err := coreutil.Mkdir(filepath.Dir(dst)); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "mkdir %s failed", filepath.Dir(dst))
+	}
+	if out, err := exec.Command("/bin/sh", "-c", downloadCommand(dst, rawURL)).CombinedOutput(); err != nil {
+		return nil, errors.Errorf("download %s failed: %s", rawURL, string(out))
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
+
+// imagesInManifests returns the images referenced by the "image" fields in the yaml documents.
+// The images are normalized into the form of registry/name:tag or registry/namespace/name:tag, which are the
+// ones supported by the artifact, it fails if an image can't be normalized.
+func imagesInManifests(data []byte) ([]string, error) {
+	var images []string
+	seen := make(map[string]struct{})
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err != io.EOF {
+				logger.Log.Warnf("skip the invalid yaml document: %s", err)
+			}
+			break
+		}
+		for _, image := range imageFields(doc) {
+			normalized, err := normalizeImage(image)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unsupported image %s", image)
+			}
+			if _, ok := seen[normalized]; ok {
+				continue
+			}
+			seen[normalized] = struct{}{}
+			images = append(images, normalized)
+		}
+	}
+	return images, nil
+}
+
+func imageFields(obj interface{}) []string {
+	var images []string
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range o {
+			if s, ok := v.(string); ok && k == "image" {
+				images = append(images, s)
+				continue
+			}
+			images = append(images, imageFields(v)...)
+		}
+	case []interface{}:
+		for _, v := range o {
+			images = append(images, imageFields(v)...)
+		}
+	}
+	return images
+}
+
+func normalizeImage(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimSpace(image))
+	if err != nil {
+		return "", err
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return "", errors.New("the image referenced by digest is not supported")
+	}
+	if strings.Count(reference.Path(named), "/") > 1 {
+		return "", errors.New("the image must be in the form of registry/name:tag or registry/namespace/name:tag")
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
@@ -0,0 +1,126 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+)
+
+func TestImagesInManifests(t *testing.T) {
+	logger.Log = logger.NewLogger(t.TempDir(), false)
+
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: app
+        image: bitnami/redis:6.2
+      - name: sidecar
+        image: quay.io/prometheus/node-exporter:v1.3.1
+---
+apiVersion: v1
+kind: Pod
+spec:
+  containers:
+  - name: app
+    image: bitnami/redis:6.2
+  - name: pause
+    image: registry.k8s.io/pause:3.6
+`
+	got, err := imagesInManifests([]byte(manifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct{}{
+		"docker.io/library/busybox:latest":        {},
+		"docker.io/bitnami/redis:6.2":             {},
+		"quay.io/prometheus/node-exporter:v1.3.1": {},
+		"registry.k8s.io/pause:3.6":               {},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("imagesInManifests() = %v, want %v", got, want)
+	}
+	for _, image := range got {
+		if _, ok := want[image]; !ok {
+			t.Errorf("unexpected image %s", image)
+		}
+	}
+
+	for _, image := range []string{
+		"docker.io/library/nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		"registry.k8s.io/sig-storage/csi/livenessprobe:v2.7.0",
+	} {
+		if _, err := imagesInManifests([]byte("image: " + image)); err == nil {
+			t.Errorf("imagesInManifests() with the image %s should fail", image)
+		}
+	}
+}
+
+func TestResolveFromArtifact(t *testing.T) {
+	logger.Log = logger.NewLogger(t.TempDir(), false)
+
+	root := t.TempDir()
+	addon := kubekeyapiv1alpha2.Addon{
+		Name: "nfs-client",
+		Sources: kubekeyapiv1alpha2.Sources{
+			Chart: kubekeyapiv1alpha2.Chart{
+				Name:    "nfs-client-provisioner",
+				Repo:    "https://charts.kubesphere.io/main",
+				Version: "4.0.11",
+			},
+			Yaml: kubekeyapiv1alpha2.Yaml{
+				Path: []string{"https://example.com/deploy/a.yaml", "https://example.com/deploy/b.yaml", "/local/c.yaml"},
+			},
+		},
+	}
+	for _, f := range []string{chartFile(root, addon.Sources.Chart), yamlFile(root, "https://example.com/deploy/a.yaml")} {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := resolveFromArtifact(root, addon)
+	wantChart := kubekeyapiv1alpha2.Chart{
+		Name: "nfs-client-provisioner-4.0.11.tgz",
+		Path: filepath.Join(root, chartsDir),
+	}
+	if !reflect.DeepEqual(got.Sources.Chart, wantChart) {
+		t.Errorf("chart = %+v, want %+v", got.Sources.Chart, wantChart)
+	}
+	wantYaml := []string{yamlFile(root, "https://example.com/deploy/a.yaml"), "https://example.com/deploy/b.yaml", "/local/c.yaml"}
+	if !reflect.DeepEqual(got.Sources.Yaml.Path, wantYaml) {
+		t.Errorf("yaml = %v, want %v", got.Sources.Yaml.Path, wantYaml)
+	}
+	if addon.Sources.Yaml.Path[0] != "https://example.com/deploy/a.yaml" {
+		t.Errorf("the original addon is modified")
+	}
+}
@@ -44,3 +44,28 @@ func (a *AddonsModule) Init() {
 		install,
 	}
 }
+
+// ArtifactAddonsModule packs the addons into the artifact, it must be executed before the images are saved.
+type ArtifactAddonsModule struct {
+	common.ArtifactModule
+	Skip bool
+}
+
+func (a *ArtifactAddonsModule) IsSkip() bool {
+	return a.Skip
+}
+
+func (a *ArtifactAddonsModule) Init() {
+	a.Name = "ArtifactAddonsModule"
+	a.Desc = "Save the charts and yaml of the addons into the artifact"
+
+	save := &task.LocalTask{
+		Name:   "SaveAddons",
+		Desc:   "Save the charts and yaml of the addons",
+		Action: new(SaveAddons),
+	}
+
+	a.Tasks = []task.Interface{
+		save,
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/pkg/errors"
+
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
@@ -33,9 +35,68 @@ func (i *Install) Execute(runtime connector.Runtime) error {
 	nums := len(i.KubeConf.Cluster.Addons)
 	for index, addon := range i.KubeConf.Cluster.Addons {
 		logger.Log.Messagef(runtime.RemoteHost().GetName(), "Install addon [%v-%v]: %s", nums, index, addon.Name)
+		if i.KubeConf.Arg.Artifact != "" {
+			addon = resolveFromArtifact(runtime.GetWorkDir(), addon)
+		}
 		if err := InstallAddons(i.KubeConf, &addon, filepath.Join(runtime.GetWorkDir(), fmt.Sprintf("config-%s", runtime.GetObjName()))); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SaveAddons packs the charts and the remote yaml of the addons into the artifact, and adds the images referenced
+// by them to the images of the manifest.
+type SaveAddons struct {
+	common.ArtifactAction
+}
+
+func (s *SaveAddons) Execute(runtime connector.Runtime) error {
+	root := filepath.Join(runtime.GetWorkDir(), common.Artifact)
+	for _, addon := range s.Manifest.Spec.Addons {
+		var images []string
+		if addon.Sources.Chart.Name != "" {
+			if isRemote(addon.Sources.Chart.ValuesFile) {
+				if _, err := saveRemoteFile(root, addon.Sources.Chart.ValuesFile, s.Manifest.Arg.DownloadCommand); err != nil {
+					return err
+				}
+			}
+			chartImages, err := saveChart(root, addon)
+			if err != nil {
+				return err
+			}
+			images = append(images, chartImages...)
+		}
+		for _, y := range addon.Sources.Yaml.Path {
+			if !isRemote(y) {
+				continue
+			}
+			data, err := saveRemoteFile(root, y, s.Manifest.Arg.DownloadCommand)
+			if err != nil {
+				return err
+			}
+			yamlImages, err := imagesInManifests(data)
+			if err != nil {
+				return errors.Wrapf(err, "get the images of %s failed", y)
+			}
+			images = append(images, yamlImages...)
+		}
+		s.appendImages(addon.Name, images)
+	}
+	return nil
+}
+
+func (s *SaveAddons) appendImages(addon string, images []string) {
+	exist := make(map[string]struct{}, len(s.Manifest.Spec.Images))
+	for _, image := range s.Manifest.Spec.Images {
+		exist[image] = struct{}{}
+	}
+	for _, image := range images {
+		if _, ok := exist[image]; ok {
+			continue
+		}
+		exist[image] = struct{}{}
+		logger.Log.Infof("Add the image %s referenced by the addon %s", image, addon)
+		s.Manifest.Spec.Images = append(s.Manifest.Spec.Images, image)
+	}
+}
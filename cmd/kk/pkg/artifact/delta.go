@@ -0,0 +1,274 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+	coreutil "github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+// DeltaManifestFile is the file at the root of a delta artifact, which describes the delta.
+// The leading dot makes it the first entry of the archive, so that it can be found without reading the whole artifact.
+const DeltaManifestFile = ".delta.json"
+
+// DeltaManifest describes a delta artifact, which only contains the files not present in its base artifact.
+// The images are saved in the OCI layout whose blobs are named by their digests, so only the new layers are contained.
+type DeltaManifest struct {
+	// Base is the sha256 of the base artifact.
+	Base string `json:"base"`
+	// Files are the sha256 of all the files in the artifact after the delta is merged onto the base.
+	Files map[string]string `json:"files"`
+	// Added are the files contained by the delta.
+	Added []string `json:"added"`
+}
+
+// CreateDelta stages the files of the artifact dir which are not the same as the ones in the base artifact
+// into the delta dir, and writes the delta manifest into it. The artifact dir is left untouched.
+func CreateDelta(base, dir, deltaDir string) (*DeltaManifest, error) {
+	baseSum, baseFiles, err := tarFileSums(base)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read the base artifact %s failed", base)
+	}
+	if err := os.RemoveAll(deltaDir); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "remove %s failed", deltaDir)
+	}
+	if err := os.MkdirAll(deltaDir, 0755); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "mkdir %s failed", deltaDir)
+	}
+
+	delta := &DeltaManifest{
+		Base:  baseSum,
+		Files: make(map[string]string),
+	}
+	err = filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// the index is created for the delta itself after the delta is staged
+		switch name {
+		case DeltaManifestFile, IndexFile, IndexSignatureFile:
+			return nil
+		}
+		sum, err := fileSum(path)
+		if err != nil {
+			return err
+		}
+		delta.Files[name] = sum
+		if baseFiles[name] == sum {
+			return nil
+		}
+		delta.Added = append(delta.Added, name)
+		return stageFile(path, filepath.Join(deltaDir, name))
+	})
+	if err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "walk %s failed", dir)
+	}
+	sort.Strings(delta.Added)
+	logger.Log.Infof("%d of %d files are contained by the delta of the base artifact %s", len(delta.Added), len(delta.Files), base)
+
+	data, err := json.MarshalIndent(delta, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := os.WriteFile(filepath.Join(deltaDir, DeltaManifestFile), data, 0644); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "write %s failed", DeltaManifestFile)
+	}
+	return delta, nil
+}
+
+// stageFile hard links the file into the delta dir, or copies it if it can't be linked.
+func stageFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// ReadDeltaManifest returns the delta manifest of the artifact, it's nil if the artifact is not a delta.
+// The delta manifest is the first entry of a delta, so only the first entry is read.
+func ReadDeltaManifest(artifact string) (*DeltaManifest, error) {
+	var delta *DeltaManifest
+	err := walkTar(artifact, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		if filepath.Clean(hdr.Name) != DeltaManifestFile {
+			return true, nil
+		}
+		delta = new(DeltaManifest)
+		return true, json.NewDecoder(r).Decode(delta)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s of the artifact %s failed", DeltaManifestFile, artifact)
+	}
+	return delta, nil
+}
+
+// MergeDelta unarchives the delta artifact into the dir where its base artifact has been unarchived.
+// The files of the base are checked before the delta is merged, and the files of the delta are checked afterwards.
+func MergeDelta(artifact, dir string, delta *DeltaManifest) error {
+	added := make(map[string]struct{}, len(delta.Added))
+	for _, name := range delta.Added {
+		added[name] = struct{}{}
+	}
+	for name, sum := range delta.Files {
+		if _, ok := added[name]; ok {
+			continue
+		}
+		if err := checkFileSum(filepath.Join(dir, name), sum); err != nil {
+			return errors.Wrapf(err, "the artifact unarchived in %s is not the base of the delta %s, "+
+				"please unarchive the base artifact first", dir, artifact)
+		}
+	}
+
+	if err := coreutil.Untar(artifact, dir); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "unArchive %s failed", artifact)
+	}
+	for _, name := range delta.Added {
+		if err := checkFileSum(filepath.Join(dir, name), delta.Files[name]); err != nil {
+			return errors.Wrapf(err, "the delta %s is corrupted", artifact)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, DeltaManifestFile)); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+	logger.Log.Infof("The delta %s has been merged into %s, %d files are added or updated", artifact, dir, len(delta.Added))
+	return nil
+}
+
+// tarFileSums returns the sha256 of the artifact and the sha256 of each file in it.
+func tarFileSums(artifact string) (string, map[string]string, error) {
+	f, err := os.Open(artifact)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	files := make(map[string]string)
+	if err := walkTarReader(io.TeeReader(f, h), func(hdr *tar.Header, r io.Reader) (bool, error) {
+		fh := sha256.New()
+		if _, err := io.Copy(fh, r); err != nil {
+			return false, err
+		}
+		files[filepath.Clean(hdr.Name)] = fmt.Sprintf("%x", fh.Sum(nil))
+		return false, nil
+	}); err != nil {
+		return "", nil, err
+	}
+	// read the rest of the gzip stream, e.g. the padding of the tar, which is not consumed by the tar reader.
+	if _, err := io.Copy(h, f); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), files, nil
+}
+
+func walkTar(artifact string, fn func(hdr *tar.Header, r io.Reader) (bool, error)) error {
+	f, err := os.Open(artifact)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return walkTarReader(f, fn)
+}
+
+// walkTarReader calls the fn for each regular file in the gzipped tar stream until it returns true.
+func walkTarReader(r io.Reader, fn func(hdr *tar.Header, r io.Reader) (bool, error)) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		stop, err := fn(hdr, tr)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+}
+
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func checkFileSum(path, sum string) error {
+	actual, err := fileSum(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if actual != sum {
+		return errors.Errorf("the sha256 of %s is %s, but %s is expected", path, actual, sum)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+	coreutil "github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func archive(t *testing.T, dir, dst string) string {
+	if err := coreutil.Tar(dir, dst, dir); err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+func TestDelta(t *testing.T) {
+	logger.Log = logger.NewLogger(t.TempDir(), false)
+	tmp := t.TempDir()
+
+	baseDir := filepath.Join(tmp, "base")
+	writeFiles(t, baseDir, map[string]string{
+		"kube/v1.22.12/amd64/kubeadm":   "kubeadm v1.22.12",
+		"images/blobs/sha256/aaa":       "layer a",
+		"images/index.json":             `{"manifests":["a"]}`,
+		"repository/amd64/centos/7.iso": "iso",
+	})
+	base := archive(t, baseDir, filepath.Join(tmp, "base.tar.gz"))
+
+	targetFiles := map[string]string{
+		"kube/v1.22.12/amd64/kubeadm":   "kubeadm v1.22.12",
+		"kube/v1.22.15/amd64/kubeadm":   "kubeadm v1.22.15",
+		"images/blobs/sha256/aaa":       "layer a",
+		"images/blobs/sha256/bbb":       "layer b",
+		"images/index.json":             `{"manifests":["a","b"]}`,
+		"repository/amd64/centos/7.iso": "iso",
+	}
+	targetDir := filepath.Join(tmp, "target")
+	writeFiles(t, targetDir, targetFiles)
+	deltaDir := filepath.Join(tmp, "delta")
+	delta, err := CreateDelta(base, targetDir, deltaDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAdded := []string{"images/blobs/sha256/bbb", "images/index.json", "kube/v1.22.15/amd64/kubeadm"}
+	if !reflect.DeepEqual(delta.Added, wantAdded) {
+		t.Fatalf("added = %v, want %v", delta.Added, wantAdded)
+	}
+	if coreutil.IsExist(filepath.Join(deltaDir, "images/blobs/sha256/aaa")) {
+		t.Fatal("the file present in the base is staged")
+	}
+	if !coreutil.IsExist(filepath.Join(targetDir, "images/blobs/sha256/aaa")) || coreutil.IsExist(filepath.Join(targetDir, DeltaManifestFile)) {
+		t.Fatal("the artifact dir is changed")
+	}
+	deltaFile := archive(t, deltaDir, filepath.Join(tmp, "delta.tar.gz"))
+
+	got, err := ReadDeltaManifest(deltaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, delta) {
+		t.Fatalf("ReadDeltaManifest() = %+v, want %+v", got, delta)
+	}
+	if got, err := ReadDeltaManifest(base); err != nil || got != nil {
+		t.Fatalf("ReadDeltaManifest() of a full artifact = %v, %v", got, err)
+	}
+
+	// the delta can't be merged without its base
+	if err := MergeDelta(deltaFile, t.TempDir(), delta); err == nil {
+		t.Fatal("the delta is merged without its base")
+	}
+
+	workDir := filepath.Join(tmp, "work")
+	if err := coreutil.Untar(base, workDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeDelta(deltaFile, workDir, delta); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range targetFiles {
+		data, err := os.ReadFile(filepath.Join(workDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+	if coreutil.IsExist(filepath.Join(workDir, DeltaManifestFile)) {
+		t.Error("the delta manifest is left in the work dir")
+	}
+}
@@ -0,0 +1,237 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// IndexFile is the file at the root of the artifact with the sha256 of every other file in it,
+	// including the blobs of the images.
+	IndexFile = "kubekey-index.json"
+	// IndexSignatureFile is the base64 encoded ed25519 signature of the IndexFile.
+	IndexSignatureFile = "kubekey-index.json.sig"
+)
+
+// Index is the sha256 content index of an artifact.
+type Index struct {
+	Files map[string]string `json:"files"`
+}
+
+// CreateIndex writes the index of the files in the artifact dir, and signs it with the ed25519 private key if it's set.
+func CreateIndex(dir, signKey string) (*Index, error) {
+	for _, f := range []string{IndexFile, IndexSignatureFile} {
+		if err := os.Remove(filepath.Join(dir, f)); err != nil && !os.IsNotExist(err) {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	index := &Index{Files: make(map[string]string)}
+	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileSum(path)
+		if err != nil {
+			return err
+		}
+		index.Files[filepath.ToSlash(name)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "walk %s failed", dir)
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, IndexFile), data, 0644); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "write %s failed", IndexFile)
+	}
+
+	if signKey == "" {
+		return index, nil
+	}
+	key, err := readPrivateKey(signKey)
+	if err != nil {
+		return nil, err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(filepath.Join(dir, IndexSignatureFile), []byte(signature), 0644); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "write %s failed", IndexSignatureFile)
+	}
+	return index, nil
+}
+
+// VerifyArtifact checks every file in the artifact against its index, and the signature of the index
+// if the public key is set. The returned index is nil if the artifact has no index and the public key is not set.
+func VerifyArtifact(artifact, publicKey string) (*Index, error) {
+	var indexData, signature []byte
+	sums := make(map[string]string)
+	err := walkTar(artifact, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		name := filepath.ToSlash(filepath.Clean(hdr.Name))
+		switch name {
+		case IndexFile:
+			data, err := io.ReadAll(r)
+			indexData = data
+			return false, err
+		case IndexSignatureFile:
+			data, err := io.ReadAll(r)
+			signature = data
+			return false, err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return false, err
+		}
+		sums[name] = fmt.Sprintf("%x", h.Sum(nil))
+		return false, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "read the artifact %s failed", artifact)
+	}
+
+	if indexData == nil {
+		if publicKey != "" {
+			return nil, errors.Errorf("the artifact %s has no index to verify", artifact)
+		}
+		return nil, nil
+	}
+	if publicKey != "" {
+		if err := verifySignature(publicKey, indexData, signature); err != nil {
+			return nil, errors.Wrapf(err, "verify the signature of the artifact %s failed", artifact)
+		}
+	}
+
+	index := new(Index)
+	if err := json.Unmarshal(indexData, index); err != nil {
+		return nil, errors.Wrapf(err, "parse %s of the artifact %s failed", IndexFile, artifact)
+	}
+	if mismatches := compareSums(index.Files, sums); len(mismatches) != 0 {
+		return index, errors.Errorf("the artifact %s doesn't match its index:\n%s", artifact, strings.Join(mismatches, "\n"))
+	}
+	return index, nil
+}
+
+func compareSums(expected, actual map[string]string) []string {
+	var mismatches []string
+	for name, sum := range expected {
+		got, ok := actual[name]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s: missing", name))
+		case got != sum:
+			mismatches = append(mismatches, fmt.Sprintf("%s: sha256 %s, expected %s", name, got, sum))
+		}
+	}
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: not in the index", name))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+func verifySignature(publicKey string, data, signature []byte) error {
+	key, err := readPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if signature == nil {
+		return errors.New("the artifact is not signed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return errors.Wrapf(err, "decode %s failed", IndexSignatureFile)
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return errors.New("the signature doesn't match the public key")
+	}
+	return nil
+}
+
+// readPrivateKey reads the PKCS #8 ed25519 private key, e.g. generated by "openssl genpkey -algorithm ed25519".
+func readPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the private key %s failed", path)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the private key %s is not an ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+// readPublicKey reads the PKIX ed25519 public key, e.g. generated by "openssl pkey -pubout".
+func readPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the public key %s failed", path)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("the public key %s is not an ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "read %s failed", path)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data is found in %s", path)
+	}
+	return block, nil
+}
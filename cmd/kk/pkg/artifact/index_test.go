@@ -0,0 +1,145 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+	coreutil "github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+func writeKeyPair(t *testing.T, dir string) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDer, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privFile, pubFile := filepath.Join(dir, "key.pem"), filepath.Join(dir, "pub.pem")
+	if err := os.WriteFile(privFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privFile, pubFile
+}
+
+func TestVerifyArtifact(t *testing.T) {
+	logger.Log = logger.NewLogger(t.TempDir(), false)
+	tmp := t.TempDir()
+	keyFile, pubFile := writeKeyPair(t, tmp)
+	_, otherPubFile := writeKeyPair(t, t.TempDir())
+
+	dir := filepath.Join(tmp, "artifact")
+	writeFiles(t, dir, map[string]string{
+		"kube/v1.22.12/amd64/kubeadm": "kubeadm v1.22.12",
+		"images/blobs/sha256/aaa":     "layer a",
+		"images/index.json":           `{"manifests":["a"]}`,
+	})
+	index, err := CreateIndex(dir, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Files) != 3 {
+		t.Fatalf("the index has %d files, want 3", len(index.Files))
+	}
+	signed := archive(t, dir, filepath.Join(tmp, "signed.tar.gz"))
+
+	if _, err := VerifyArtifact(signed, pubFile); err != nil {
+		t.Fatalf("verify the signed artifact: %v", err)
+	}
+	if _, err := VerifyArtifact(signed, otherPubFile); err == nil {
+		t.Fatal("the artifact is verified with another public key")
+	}
+
+	writeFiles(t, dir, map[string]string{"images/blobs/sha256/aaa": "tampered"})
+	tampered := archive(t, dir, filepath.Join(tmp, "tampered.tar.gz"))
+	if _, err := VerifyArtifact(tampered, pubFile); err == nil {
+		t.Fatal("the tampered artifact is verified")
+	}
+
+	for _, f := range []string{IndexFile, IndexSignatureFile} {
+		if err := os.Remove(filepath.Join(dir, f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unsigned := archive(t, dir, filepath.Join(tmp, "unsigned.tar.gz"))
+	if index, err := VerifyArtifact(unsigned, ""); err != nil || index != nil {
+		t.Fatalf("VerifyArtifact() of an artifact without index = %v, %v", index, err)
+	}
+	if _, err := VerifyArtifact(unsigned, pubFile); err == nil {
+		t.Fatal("the artifact without index is verified with a public key")
+	}
+}
+
+func TestDeltaAfterFullExport(t *testing.T) {
+	logger.Log = logger.NewLogger(t.TempDir(), false)
+	tmp := t.TempDir()
+	keyFile, pubFile := writeKeyPair(t, tmp)
+
+	// the full export leaves its index in the artifact dir
+	dir := filepath.Join(tmp, "artifact")
+	writeFiles(t, dir, map[string]string{
+		"kube/v1.22.12/amd64/kubeadm": "kubeadm v1.22.12",
+		"images/blobs/sha256/aaa":     "layer a",
+	})
+	if _, err := CreateIndex(dir, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	base := archive(t, dir, filepath.Join(tmp, "base.tar.gz"))
+
+	writeFiles(t, dir, map[string]string{"kube/v1.22.15/amd64/kubeadm": "kubeadm v1.22.15"})
+	deltaDir := filepath.Join(tmp, "delta")
+	delta, err := CreateDelta(base, dir, deltaDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{IndexFile, IndexSignatureFile} {
+		if _, ok := delta.Files[f]; ok {
+			t.Fatalf("%s is in the delta", f)
+		}
+	}
+	if _, err := CreateIndex(deltaDir, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	deltaFile := archive(t, deltaDir, filepath.Join(tmp, "delta.tar.gz"))
+
+	if _, err := VerifyArtifact(deltaFile, pubFile); err != nil {
+		t.Fatalf("verify the delta: %v", err)
+	}
+	workDir := filepath.Join(tmp, "work")
+	if err := coreutil.Untar(base, workDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeDelta(deltaFile, workDir, delta); err != nil {
+		t.Fatalf("merge the delta: %v", err)
+	}
+}
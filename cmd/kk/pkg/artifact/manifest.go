@@ -34,6 +34,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/client/kubernetes"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/util/osrelease"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -55,6 +56,7 @@ func CreateManifest(arg common.Argument, name string) error {
 	containerSet := mapset.NewThreadUnsafeSet()
 	imagesSet := mapset.NewThreadUnsafeSet()
 	osSet := mapset.NewThreadUnsafeSet()
+	isoSet := mapset.NewThreadUnsafeSet()
 
 	maxKubeletVersion := versionutil.MustParseGeneric("v0.0.0")
 	kubernetesDistribution := kubekeyv1alpha2.KubernetesDistribution{}
@@ -64,6 +66,10 @@ func CreateManifest(arg common.Argument, name string) error {
 			Type:    containerStrArr[0],
 			Version: containerStrArr[1],
 		}
+		// cri-o reports itself as "cri-o", but kubekey names the runtime "crio".
+		if containerRuntime.Type == "cri-o" {
+			containerRuntime.Type = kubekeyv1alpha2.Crio
+		}
 		if containerRuntime.Type == "containerd" &&
 			versionutil.MustParseSemantic(containerRuntime.Version).LessThan(versionutil.MustParseSemantic("1.6.2")) {
 			containerRuntime.Version = "1.6.2"
@@ -88,35 +94,25 @@ func CreateManifest(arg common.Argument, name string) error {
 			}
 		}
 
-		// todo: for now, the cases only have ubuntu, centos. Ant it need to check all linux distribution.
-		var (
-			id, version string
-		)
-		osImageArr := strings.Split(node.Status.NodeInfo.OSImage, " ")
-		switch strings.ToLower(osImageArr[0]) {
-		case "ubuntu":
-			id = "ubuntu"
-			v := strings.Split(osImageArr[1], ".")
-			version = fmt.Sprintf("%s.%s", v[0], v[1])
-		case "centos":
-			id = "centos"
-			version = osImageArr[2]
-		case "rhel":
-			id = "rhel"
-			version = osImageArr[2]
-		default:
-			id = strings.ToLower(osImageArr[0])
-			version = "Can't get the os version. Please edit it manually."
+		// the labels of node-feature-discovery are read from the /etc/os-release of the node, which is more
+		// accurate than the os image.
+		release := releaseFromLabels(node.Labels)
+		if release == nil {
+			release = releaseFromOSImage(node.Status.NodeInfo.OSImage)
 		}
-
-		osObj := kubekeyv1alpha2.OperatingSystem{
-			Arch:    node.Status.NodeInfo.Architecture,
-			Type:    node.Status.NodeInfo.OperatingSystem,
-			Id:      id,
-			Version: version,
-			OsImage: node.Status.NodeInfo.OSImage,
+		if release == nil {
+			osImageArr := strings.Split(node.Status.NodeInfo.OSImage, " ")
+			release = &osrelease.Data{
+				ID:        strings.ToLower(osImageArr[0]),
+				VersionID: "Can't get the os version. Please edit it manually.",
+			}
 		}
+		osObj, iso := operatingSystem(release, node.Status.NodeInfo.Architecture, node.Status.NodeInfo.OSImage)
+		osObj.Type = node.Status.NodeInfo.OperatingSystem
 		osSet.Add(osObj)
+		if iso != "" {
+			isoSet.Add(iso)
+		}
 
 		kubeletStrArr := strings.Split(node.Status.NodeInfo.KubeletVersion, "+")
 		kubeletVersion := kubeletStrArr[0]
@@ -170,10 +166,37 @@ func CreateManifest(arg common.Argument, name string) error {
 		Images: imageArr,
 	}
 
+	if err := writeManifest(arg.FilePath, options); err != nil {
+		return err
+	}
+	printISOHints(isoSet)
+	return nil
+}
+
+// printISOHints prints the repository iso files which are not released with kk.
+func printISOHints(isoSet mapset.Set) {
+	if isoSet.Cardinality() == 0 {
+		return
+	}
+	isoArr := make([]string, 0, isoSet.Cardinality())
+	for _, v := range isoSet.ToSlice() {
+		isoArr = append(isoArr, v.(string))
+	}
+	sort.Strings(isoArr)
+	fmt.Println("The following repository iso files are not released with KubeKey, please build them with hack/gen-repository-iso and set the localPath or url in the manifest if they are needed:")
+	for _, iso := range isoArr {
+		fmt.Printf("  %s\n", iso)
+	}
+}
+
+func writeManifest(fileName string, options *templates.Options) error {
 	manifestStr, err := templates.RenderManifest(options)
+	if err != nil {
+		return err
+	}
 
-	if err := ioutil.WriteFile(arg.FilePath, []byte(manifestStr), 0644); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("write file %s failed", arg.FilePath))
+	if err := ioutil.WriteFile(fileName, []byte(manifestStr), 0644); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("write file %s failed", fileName))
 	}
 
 	fmt.Println("Generate KubeKey manifest file successfully")
@@ -0,0 +1,264 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+	mapset "github.com/deckarep/golang-set"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/artifact/templates"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/images"
+	"github.com/kubesphere/kubekey/pkg/util/osrelease"
+)
+
+// kubeadmImages are the images only used by the clusters created by kubeadm.
+var kubeadmImages = map[string]struct{}{
+	"etcd":                    {},
+	"kube-apiserver":          {},
+	"kube-controller-manager": {},
+	"kube-scheduler":          {},
+	"kube-proxy":              {},
+}
+
+// clusterImages are the names of the images which may be deployed by kk, see images.GetImage.
+var clusterImages = []string{
+	"etcd",
+	"pause",
+	"kube-apiserver",
+	"kube-controller-manager",
+	"kube-scheduler",
+	"kube-proxy",
+	"coredns",
+	"k8s-dns-node-cache",
+	"calico-kube-controllers",
+	"calico-cni",
+	"calico-node",
+	"calico-flexvol",
+	"calico-typha",
+	"cilium",
+	"cilium-operator-generic",
+	"flannel",
+	"kubeovn",
+	"multus",
+	"haproxy",
+	"nginx",
+	"envoy",
+	"kubevip",
+	"kata-deploy",
+	"node-feature-discovery",
+}
+
+// CreateManifestFromConfig creates the manifest from the cluster config file specified by arg.FilePath,
+// it doesn't need to access the cluster. The operating systems are read from the hosts if detectOS is true.
+func CreateManifestFromConfig(arg common.Argument, name, fileName string, detectOS bool) error {
+	checkFileExists(fileName)
+
+	runtime, err := common.NewKubeRuntime(common.File, arg)
+	if err != nil {
+		return err
+	}
+	cluster := runtime.Cluster
+
+	archSet := make(map[string]struct{})
+	for _, host := range runtime.GetAllHosts() {
+		archSet[host.GetArch()] = struct{}{}
+	}
+	archArr := make([]string, 0, len(archSet))
+	for arch := range archSet {
+		archArr = append(archArr, arch)
+	}
+	sort.Strings(archArr)
+
+	imageArr, err := configImages(runtime)
+	if err != nil {
+		return err
+	}
+
+	options := &templates.Options{
+		Name:   name,
+		Arches: archArr,
+		KubernetesDistributions: []kubekeyv1alpha2.KubernetesDistribution{
+			{Type: cluster.Kubernetes.Type, Version: cluster.Kubernetes.Version},
+		},
+		Components: kubekeyv1alpha2.Components{
+			Helm:              kubekeyv1alpha2.Helm{Version: kubekeyv1alpha2.DefaultHelmVersion},
+			CNI:               kubekeyv1alpha2.CNI{Version: kubekeyv1alpha2.DefaultCniVersion},
+			ETCD:              kubekeyv1alpha2.ETCD{Version: kubekeyv1alpha2.DefaultEtcdVersion},
+			Crictl:            kubekeyv1alpha2.Crictl{Version: kubekeyv1alpha2.DefaultCrictlVersion},
+			ContainerRuntimes: configContainerRuntimes(cluster.Kubernetes.ContainerManager),
+		},
+		Images: imageArr,
+	}
+
+	if len(runtime.GetHostsByRole(common.Registry)) != 0 {
+		switch cluster.Registry.Type {
+		case common.Harbor:
+			options.Components.Harbor.Version = kubekeyv1alpha2.DefaultHarborVersion
+			options.Components.DockerCompose.Version = kubekeyv1alpha2.DefaultDockerComposeVersion
+		default:
+			options.Components.DockerRegistry.Version = kubekeyv1alpha2.DefaultRegistryVersion
+		}
+	}
+
+	isoSet := mapset.NewThreadUnsafeSet()
+	if detectOS {
+		osArr, err := detectOperatingSystems(runtime, isoSet)
+		if err != nil {
+			return err
+		}
+		options.OperatingSystems = osArr
+	}
+
+	if len(cluster.Addons) != 0 {
+		data, err := yaml.Marshal(struct {
+			Addons []kubekeyv1alpha2.Addon `json:"addons"`
+		}{Addons: cluster.Addons})
+		if err != nil {
+			return errors.Wrap(err, "marshal the addons failed")
+		}
+		options.Addons = indent(strings.TrimSpace(string(data)), "  ")
+	}
+
+	if err := writeManifest(fileName, options); err != nil {
+		return err
+	}
+	if detectOS {
+		printISOHints(isoSet)
+	} else {
+		fmt.Println("The operating systems of the nodes are unknown without --detect-os, please add them to the manifest manually if the repository iso files are needed.")
+	}
+	if cluster.KubeSphere.Enabled {
+		fmt.Println("The images of KubeSphere are not included, please add them to the manifest manually.")
+	}
+	return nil
+}
+
+// detectOperatingSystems reads the /etc/os-release of all the hosts in the cluster config.
+func detectOperatingSystems(runtime *common.KubeRuntime, isoSet mapset.Set) ([]kubekeyv1alpha2.OperatingSystem, error) {
+	p := pipeline.Pipeline{
+		Name:    "DetectOSPipeline",
+		Modules: []module.Module{&OSReleaseModule{}},
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return nil, err
+	}
+
+	osSet := mapset.NewThreadUnsafeSet()
+	for _, host := range runtime.GetAllHosts() {
+		v, ok := host.GetCache().Get(OSRelease)
+		if !ok {
+			return nil, errors.Errorf("get the os release of %s failed", host.GetName())
+		}
+		release := v.(*osrelease.Data)
+		osObj, iso := operatingSystem(release, host.GetArch(), release.PrettyName)
+		osSet.Add(osObj)
+		if iso != "" {
+			isoSet.Add(iso)
+		}
+	}
+
+	osArr := make([]kubekeyv1alpha2.OperatingSystem, 0, osSet.Cardinality())
+	for _, v := range osSet.ToSlice() {
+		osArr = append(osArr, v.(kubekeyv1alpha2.OperatingSystem))
+	}
+	sort.Slice(osArr, func(i, j int) bool {
+		return fmt.Sprintf("%s-%s-%s", osArr[i].Id, osArr[i].Version, osArr[i].Arch) < fmt.Sprintf("%s-%s-%s", osArr[j].Id, osArr[j].Version, osArr[j].Arch)
+	})
+	return osArr, nil
+}
+
+// configImages returns the images enabled by the cluster config, in the form of registry/namespace/name:tag
+// which is required by the artifact export.
+func configImages(runtime *common.KubeRuntime) ([]string, error) {
+	cluster := *runtime.Cluster
+	// the images are exported from their upstream registries rather than the private registry of the cluster.
+	cluster.Registry.PrivateRegistry = ""
+	cluster.Registry.NamespaceOverride = ""
+	kubeConf := &common.KubeConf{
+		ClusterName: runtime.ClusterName,
+		Cluster:     &cluster,
+		Arg:         runtime.Arg,
+	}
+
+	var list []images.Image
+	for _, name := range clusterImages {
+		if _, ok := kubeadmImages[name]; ok && cluster.Kubernetes.Type != common.Kubernetes {
+			continue
+		}
+		list = append(list, images.GetImage(runtime, kubeConf, name))
+	}
+	list = append(list, images.StorageImages(runtime, kubeConf)...)
+	if cluster.KubeSphere.Enabled || cluster.Storage.DefaultStorageClass == kubekeyv1alpha2.OpenEBS {
+		for _, name := range []string{"provisioner-localpv", "linux-utils"} {
+			image := images.GetImage(runtime, kubeConf, name)
+			image.Enable = true
+			list = append(list, image)
+		}
+	}
+
+	imageSet := make(map[string]struct{})
+	for _, image := range list {
+		if !image.Enable {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(image.ImageName())
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse the image %s failed", image.ImageName())
+		}
+		imageSet[reference.TagNameOnly(named).String()] = struct{}{}
+	}
+	imageArr := make([]string, 0, len(imageSet))
+	for image := range imageSet {
+		imageArr = append(imageArr, image)
+	}
+	sort.Strings(imageArr)
+	return imageArr, nil
+}
+
+// configContainerRuntimes returns the container runtime binaries to download for the container manager,
+// isula is installed from the os packages so that it has no binary.
+func configContainerRuntimes(containerManager string) []kubekeyv1alpha2.ContainerRuntime {
+	switch containerManager {
+	case common.Docker:
+		return []kubekeyv1alpha2.ContainerRuntime{{Type: common.Docker, Version: kubekeyv1alpha2.DefaultDockerVersion}}
+	case common.Conatinerd:
+		return []kubekeyv1alpha2.ContainerRuntime{{Type: common.Conatinerd, Version: kubekeyv1alpha2.DefaultContainerdVersion}}
+	case common.Crio:
+		return []kubekeyv1alpha2.ContainerRuntime{{Type: common.Crio, Version: kubekeyv1alpha2.DefaultCrioVersion}}
+	default:
+		return nil
+	}
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
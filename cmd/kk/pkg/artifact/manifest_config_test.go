@@ -0,0 +1,169 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+)
+
+const sampleConfig = `apiVersion: kubekey.kubesphere.io/v1alpha2
+kind: Cluster
+metadata:
+  name: sample
+spec:
+  hosts:
+  - {name: node1, address: 172.16.0.2, internalAddress: 172.16.0.2, password: "Qcloud@123"}
+  - {name: node2, address: 172.16.0.3, internalAddress: 172.16.0.3, password: "Qcloud@123", arch: arm64}
+  roleGroups:
+    etcd:
+    - node1
+    control-plane:
+    - node1
+    worker:
+    - node2
+%s
+  kubernetes:
+    version: %s
+    type: %s
+    containerManager: %s
+  network:
+    plugin: %s
+    kubePodsCIDR: 10.233.64.0/18
+    kubeServiceCIDR: 10.233.0.0/18
+  registry:
+    type: %s
+`
+
+func TestCreateManifestFromConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		registryHosts    string
+		version          string
+		kubernetesType   string
+		containerManager string
+		plugin           string
+		registryType     string
+		images           []string
+		noImages         []string
+		runtimes         []kubekeyv1alpha2.ContainerRuntime
+		registry         bool
+		harbor           bool
+	}{
+		{
+			name:             "kubernetes with calico and docker",
+			version:          "v1.21.5",
+			kubernetesType:   common.Kubernetes,
+			containerManager: common.Docker,
+			plugin:           "calico",
+			images:           []string{"kube-apiserver:v1.21.5", "kube-proxy:v1.21.5", "calico/node", "coredns"},
+			noImages:         []string{"cilium", "registry"},
+			runtimes:         []kubekeyv1alpha2.ContainerRuntime{{Type: common.Docker, Version: kubekeyv1alpha2.DefaultDockerVersion}},
+		},
+		{
+			name:             "k3s with cilium and containerd",
+			registryHosts:    "    registry:\n    - node1",
+			version:          "v1.21.4",
+			kubernetesType:   common.K3s,
+			containerManager: common.Conatinerd,
+			plugin:           "cilium",
+			images:           []string{"cilium/cilium", "cilium/operator-generic", "coredns"},
+			noImages:         []string{"kube-apiserver", "kube-proxy", "calico"},
+			runtimes:         []kubekeyv1alpha2.ContainerRuntime{{Type: common.Conatinerd, Version: kubekeyv1alpha2.DefaultContainerdVersion}},
+			registry:         true,
+		},
+		{
+			name:             "kubernetes with isula and harbor",
+			registryHosts:    "    registry:\n    - node1",
+			version:          "v1.21.5",
+			kubernetesType:   common.Kubernetes,
+			containerManager: common.Isula,
+			plugin:           "calico",
+			registryType:     common.Harbor,
+			images:           []string{"kube-apiserver:v1.21.5", "calico/node"},
+			noImages:         []string{"cilium"},
+			harbor:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := filepath.Join(dir, "config.yaml")
+			data := fmt.Sprintf(sampleConfig, tt.registryHosts, tt.version, tt.kubernetesType, tt.containerManager, tt.plugin, tt.registryType)
+			if err := ioutil.WriteFile(config, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fileName := filepath.Join(dir, "manifest.yaml")
+			if err := CreateManifestFromConfig(common.Argument{FilePath: config}, "sample", fileName, false); err != nil {
+				t.Fatal(err)
+			}
+			content, err := ioutil.ReadFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			manifest := &kubekeyv1alpha2.Manifest{}
+			if err := yaml.Unmarshal(content, manifest); err != nil {
+				t.Fatal(err)
+			}
+			spec := manifest.Spec
+
+			if got := strings.Join(spec.Arches, ","); got != "amd64,arm64" {
+				t.Errorf("arches = %s, want amd64,arm64", got)
+			}
+			if got := spec.KubernetesDistributions; len(got) != 1 || got[0].Type != tt.kubernetesType || got[0].Version != tt.version {
+				t.Errorf("kubernetes distributions = %+v, want %s %s", got, tt.kubernetesType, tt.version)
+			}
+			for _, image := range tt.images {
+				if !containsImage(spec.Images, image) {
+					t.Errorf("%s is not in the images %v", image, spec.Images)
+				}
+			}
+			for _, image := range tt.noImages {
+				if containsImage(spec.Images, image) {
+					t.Errorf("%s is in the images %v", image, spec.Images)
+				}
+			}
+			if got := spec.Components.ContainerRuntimes; fmt.Sprint(got) != fmt.Sprint(tt.runtimes) {
+				t.Errorf("container runtimes = %+v, want %+v", got, tt.runtimes)
+			}
+			if got := spec.Components.DockerRegistry.Version != ""; got != tt.registry {
+				t.Errorf("docker registry = %v, want %v", got, tt.registry)
+			}
+			if got := spec.Components.Harbor.Version != "" && spec.Components.DockerCompose.Version != ""; got != tt.harbor {
+				t.Errorf("harbor = %v, want %v", got, tt.harbor)
+			}
+		})
+	}
+}
+
+func containsImage(images []string, name string) bool {
+	for _, image := range images {
+		if strings.Contains(image, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
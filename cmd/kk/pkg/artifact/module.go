@@ -67,6 +67,73 @@ func (a *ArchiveModule) Init() {
 	}
 }
 
+// DeltaModule stages the delta of the base artifact, it must be executed before the artifact is archived.
+type DeltaModule struct {
+	common.ArtifactModule
+	Skip bool
+}
+
+func (d *DeltaModule) IsSkip() bool {
+	return d.Skip
+}
+
+func (d *DeltaModule) Init() {
+	d.Name = "ArtifactDeltaModule"
+	d.Desc = "Create the delta of the base artifact"
+
+	createDelta := &task.LocalTask{
+		Name:   "CreateDelta",
+		Desc:   "Stage the files not present in the base artifact",
+		Action: new(GenerateDelta),
+	}
+
+	d.Tasks = []task.Interface{
+		createDelta,
+	}
+}
+
+// IndexModule writes the signed index of the artifact files, it must be executed before the artifact is archived.
+type IndexModule struct {
+	common.ArtifactModule
+}
+
+func (i *IndexModule) Init() {
+	i.Name = "ArtifactIndexModule"
+	i.Desc = "Create the index of the artifact"
+
+	createIndex := &task.LocalTask{
+		Name:   "CreateIndex",
+		Desc:   "Create and sign the sha256 index of the artifact files",
+		Action: new(GenerateIndex),
+	}
+
+	i.Tasks = []task.Interface{
+		createIndex,
+	}
+}
+
+// OSReleaseModule reads the os release of all the hosts, the results are stored in the host cache with the key OSRelease.
+type OSReleaseModule struct {
+	common.KubeModule
+}
+
+func (o *OSReleaseModule) Init() {
+	o.Name = "OSReleaseModule"
+	o.Desc = "Get the os release of the hosts"
+
+	getOSRelease := &task.RemoteTask{
+		Name:     "GetOSRelease",
+		Desc:     "Get the os release of the hosts",
+		Hosts:    o.Runtime.GetAllHosts(),
+		Action:   new(GetOSRelease),
+		Parallel: true,
+	}
+
+	o.Tasks = []task.Interface{
+		getOSRelease,
+	}
+}
+
 type UnArchiveModule struct {
 	common.KubeModule
 	Skip bool
@@ -86,6 +153,13 @@ func (u *UnArchiveModule) Init() {
 		Action: new(Md5Check),
 	}
 
+	verify := &task.LocalTask{
+		Name:    "VerifyArtifact",
+		Desc:    "Verify the KubeKey artifact against its index",
+		Prepare: &Md5AreEqual{Not: true},
+		Action:  new(Verify),
+	}
+
 	unArchive := &task.LocalTask{
 		Name:    "UnArchiveArtifact",
 		Desc:    "UnArchive the KubeKey artifact",
@@ -102,6 +176,7 @@ func (u *UnArchiveModule) Init() {
 
 	u.Tasks = []task.Interface{
 		md5Check,
+		verify,
 		unArchive,
 		createMd5File,
 	}
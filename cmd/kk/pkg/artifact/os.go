@@ -0,0 +1,188 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/util/osrelease"
+	"github.com/kubesphere/kubekey/version"
+)
+
+const (
+	// OSRelease is the key of the *osrelease.Data in the host cache.
+	OSRelease = "osRelease"
+
+	// isoReleaseURL is the url of the repository iso released with kk, the params are the kk version,
+	// the name of the iso and the arch.
+	isoReleaseURL = "https://github.com/kubesphere/kubekey/releases/download/%s/%s-%s.iso"
+
+	rpms = "rpms"
+	debs = "debs"
+
+	nfdOSReleaseIDLabel        = "feature.node.kubernetes.io/system-os_release.ID"
+	nfdOSReleaseVersionIDLabel = "feature.node.kubernetes.io/system-os_release.VERSION_ID"
+)
+
+// distribution describes the repository iso of a linux distribution built by hack/gen-repository-iso.
+type distribution struct {
+	// packages is the type of the packages in the iso, rpms or debs.
+	packages string
+	// released maps the versions whose iso is released with kk to the name of the iso,
+	// see .github/workflows/gen-repository-iso.yaml.
+	released map[string]string
+	// osImage matches the pretty name reported by the kubelet, the first group is the VERSION_ID.
+	osImage *regexp.Regexp
+}
+
+// distributions are keyed by the lower case ID in /etc/os-release.
+var distributions = map[string]distribution{
+	"ubuntu": {
+		packages: debs,
+		released: map[string]string{
+			"16.04": "ubuntu-16.04-debs",
+			"18.04": "ubuntu-18.04-debs",
+			"20.04": "ubuntu-20.04-debs",
+			"22.04": "ubuntu-22.04-debs",
+		},
+		osImage: regexp.MustCompile(`^Ubuntu (\d+\.\d+)`),
+	},
+	"debian": {
+		packages: debs,
+		released: map[string]string{
+			"9":  "debian9-debs",
+			"10": "debian10-debs",
+		},
+		osImage: regexp.MustCompile(`^Debian GNU/Linux (\d+)`),
+	},
+	"centos": {
+		packages: rpms,
+		released: map[string]string{
+			"7": "centos7-rpms",
+		},
+		osImage: regexp.MustCompile(`^CentOS (?:Linux|Stream) (\d+)`),
+	},
+	"rhel": {
+		packages: rpms,
+		osImage:  regexp.MustCompile(`^Red Hat Enterprise Linux(?: Server)? (\d+(?:\.\d+)?)`),
+	},
+	"almalinux": {
+		packages: rpms,
+		released: map[string]string{
+			"9.0": "almalinux-9.0-rpms",
+		},
+		osImage: regexp.MustCompile(`^AlmaLinux (\d+\.\d+)`),
+	},
+	"rocky": {
+		packages: rpms,
+		osImage:  regexp.MustCompile(`^Rocky Linux (\d+\.\d+)`),
+	},
+	"openeuler": {
+		packages: rpms,
+		osImage:  regexp.MustCompile(`^openEuler (\d+\.\d+)`),
+	},
+	"kylin": {
+		packages: rpms,
+		osImage:  regexp.MustCompile(`^Kylin Linux Advanced Server (V\d+)`),
+	},
+	"uos": {
+		packages: rpms,
+		osImage:  regexp.MustCompile(`^(?:UnionTech OS Server|UOS Server) (\d+)`),
+	},
+	"anolis": {
+		packages: rpms,
+		osImage:  regexp.MustCompile(`^Anolis OS (\d+(?:\.\d+)?)`),
+	},
+}
+
+// osReleaseIDs are the IDs in /etc/os-release whose case differs from the keys of distributions.
+var osReleaseIDs = map[string]string{
+	"openeuler": "openEuler",
+}
+
+// releaseFromLabels gets the os release from the labels of node-feature-discovery, which are read from
+// the /etc/os-release of the node.
+func releaseFromLabels(labels map[string]string) *osrelease.Data {
+	id, version := labels[nfdOSReleaseIDLabel], labels[nfdOSReleaseVersionIDLabel]
+	if id == "" || version == "" {
+		return nil
+	}
+	return &osrelease.Data{ID: id, VersionID: version}
+}
+
+// releaseFromOSImage gets the os release from the pretty name reported by the kubelet.
+func releaseFromOSImage(osImage string) *osrelease.Data {
+	for id, d := range distributions {
+		m := d.osImage.FindStringSubmatch(osImage)
+		if m == nil {
+			continue
+		}
+		if v, ok := osReleaseIDs[id]; ok {
+			id = v
+		}
+		return &osrelease.Data{ID: id, VersionID: m[1], PrettyName: osImage}
+	}
+	return nil
+}
+
+// operatingSystem returns the operating system of the manifest, the Id and Version must be the same as the ID
+// and VERSION_ID in /etc/os-release so that the iso can be found by the nodes. The name of the iso is returned
+// if it's not released with kk and needs to be built by hack/gen-repository-iso.
+func operatingSystem(release *osrelease.Data, arch, osImage string) (kubekeyv1alpha2.OperatingSystem, string) {
+	osObj := kubekeyv1alpha2.OperatingSystem{
+		Arch:    arch,
+		Type:    "linux",
+		Id:      release.ID,
+		Version: release.VersionID,
+		OsImage: osImage,
+	}
+
+	packages := ""
+	d, ok := distributions[strings.ToLower(release.ID)]
+	switch {
+	case ok:
+		packages = d.packages
+	case release.IsLikeFedora() || strings.Contains(release.IDLike, "rhel") || strings.Contains(release.IDLike, "centos"):
+		packages = rpms
+	case release.IsLikeDebian():
+		packages = debs
+	default:
+		return osObj, ""
+	}
+
+	if name, ok := d.released[release.VersionID]; ok {
+		if kkVersion := releaseVersion(); kkVersion != "" {
+			osObj.Repository.Iso.Url = fmt.Sprintf(isoReleaseURL, kkVersion, name, arch)
+			return osObj, ""
+		}
+	}
+	return osObj, fmt.Sprintf("%s-%s-%s-%s.iso", release.ID, release.VersionID, packages, arch)
+}
+
+// releaseVersion returns the version of kk if it's a release.
+func releaseVersion() string {
+	v, err := versionutil.ParseSemantic(version.Get().GitVersion)
+	if err != nil || v.PreRelease() != "" || v.BuildMetadata() != "" || v.Major() == 0 {
+		return ""
+	}
+	return version.Get().GitVersion
+}
@@ -0,0 +1,96 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"testing"
+
+	"github.com/kubesphere/kubekey/pkg/util/osrelease"
+)
+
+func TestReleaseFromOSImage(t *testing.T) {
+	tests := []struct {
+		osImage   string
+		id        string
+		versionID string
+	}{
+		{osImage: "Ubuntu 20.04.3 LTS", id: "ubuntu", versionID: "20.04"},
+		{osImage: "Debian GNU/Linux 10 (buster)", id: "debian", versionID: "10"},
+		{osImage: "CentOS Linux 7 (Core)", id: "centos", versionID: "7"},
+		{osImage: "Red Hat Enterprise Linux Server 7.9 (Maipo)", id: "rhel", versionID: "7.9"},
+		{osImage: "AlmaLinux 9.0 (Emerald Puma)", id: "almalinux", versionID: "9.0"},
+		{osImage: "Rocky Linux 8.6 (Green Obsidian)", id: "rocky", versionID: "8.6"},
+		{osImage: "openEuler 22.03 LTS", id: "openEuler", versionID: "22.03"},
+		{osImage: "Kylin Linux Advanced Server V10 (Sword)", id: "kylin", versionID: "V10"},
+		{osImage: "UnionTech OS Server 20", id: "uos", versionID: "20"},
+		{osImage: "Anolis OS 8.6", id: "anolis", versionID: "8.6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.osImage, func(t *testing.T) {
+			release := releaseFromOSImage(tt.osImage)
+			if release == nil {
+				t.Fatalf("releaseFromOSImage(%q) = nil", tt.osImage)
+			}
+			if release.ID != tt.id || release.VersionID != tt.versionID {
+				t.Errorf("releaseFromOSImage(%q) = %s %s, want %s %s", tt.osImage, release.ID, release.VersionID, tt.id, tt.versionID)
+			}
+		})
+	}
+
+	if release := releaseFromOSImage("Unknown Linux 1.0"); release != nil {
+		t.Errorf("releaseFromOSImage() of an unknown os = %+v, want nil", release)
+	}
+}
+
+func TestOperatingSystemISO(t *testing.T) {
+	tests := []struct {
+		name    string
+		release *osrelease.Data
+		iso     string
+	}{
+		{
+			name:    "rpm based",
+			release: &osrelease.Data{ID: "openEuler", VersionID: "22.03"},
+			iso:     "openEuler-22.03-rpms-arm64.iso",
+		},
+		{
+			name:    "deb based",
+			release: &osrelease.Data{ID: "ubuntu", VersionID: "23.04"},
+			iso:     "ubuntu-23.04-debs-arm64.iso",
+		},
+		{
+			name:    "like fedora",
+			release: &osrelease.Data{ID: "ol", IDLike: "fedora", VersionID: "8.6"},
+			iso:     "ol-8.6-rpms-arm64.iso",
+		},
+		{
+			name:    "unknown",
+			release: &osrelease.Data{ID: "arch", VersionID: "rolling"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			osObj, iso := operatingSystem(tt.release, "arm64", "")
+			if iso != tt.iso {
+				t.Errorf("iso = %q, want %q", iso, tt.iso)
+			}
+			if osObj.Id != tt.release.ID || osObj.Version != tt.release.VersionID || osObj.Arch != "arm64" {
+				t.Errorf("operatingSystem() = %+v", osObj)
+			}
+		})
+	}
+}
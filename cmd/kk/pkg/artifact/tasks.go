@@ -31,6 +31,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
 	coreutil "github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/util/osrelease"
 )
 
 type DownloadISOFile struct {
@@ -105,23 +106,101 @@ type ArchiveDependencies struct {
 }
 
 func (a *ArchiveDependencies) Execute(runtime connector.Runtime) error {
-	src := filepath.Join(runtime.GetWorkDir(), common.Artifact)
+	src := exportDir(runtime, a.Manifest.Arg)
 	if err := coreutil.Tar(src, a.Manifest.Arg.Output, src); err != nil {
 		return errors.Wrapf(errors.WithStack(err), "archive %s failed", src)
 	}
 
-	// remove the src directory
-	if err := os.RemoveAll(src); err != nil {
-		return errors.Wrapf(errors.WithStack(err), "remove %s failed", src)
+	// remove the src directory, and the artifact directory which the delta is staged from
+	for _, dir := range []string{src, filepath.Join(runtime.GetWorkDir(), common.Artifact)} {
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "remove %s failed", dir)
+		}
+	}
+	return nil
+}
+
+// exportDir returns the directory to be archived, the delta is staged out of the artifact directory.
+func exportDir(runtime connector.Runtime, arg common.ArtifactArgument) string {
+	if arg.Base != "" {
+		return filepath.Join(runtime.GetWorkDir(), common.ArtifactDelta)
+	}
+	return filepath.Join(runtime.GetWorkDir(), common.Artifact)
+}
+
+// GenerateDelta stages the files which are not present in the base artifact into the delta directory.
+type GenerateDelta struct {
+	common.ArtifactAction
+}
+
+func (g *GenerateDelta) Execute(runtime connector.Runtime) error {
+	if _, err := CreateDelta(g.Manifest.Arg.Base, filepath.Join(runtime.GetWorkDir(), common.Artifact), exportDir(runtime, g.Manifest.Arg)); err != nil {
+		return err
 	}
 	return nil
 }
 
+// GenerateIndex writes the sha256 index of the artifact files, and signs it if the sign key is set.
+type GenerateIndex struct {
+	common.ArtifactAction
+}
+
+func (g *GenerateIndex) Execute(runtime connector.Runtime) error {
+	if _, err := CreateIndex(exportDir(runtime, g.Manifest.Arg), g.Manifest.Arg.SignKey); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Verify checks the artifact against its signed index before it's unarchived.
+type Verify struct {
+	common.KubeAction
+}
+
+func (v *Verify) Execute(runtime connector.Runtime) error {
+	index, err := VerifyArtifact(v.KubeConf.Arg.Artifact, v.KubeConf.Arg.ArtifactPublicKey)
+	if err != nil {
+		if v.KubeConf.Arg.SkipArtifactVerify {
+			logger.Log.Warningf("skip the artifact verification: %v", err)
+			return nil
+		}
+		return errors.Wrap(err, "the artifact verification failed, use --skip-artifact-verify to ignore it")
+	}
+	if index == nil {
+		logger.Log.Warningf("the artifact %s has no index, skip the verification", v.KubeConf.Arg.Artifact)
+		return nil
+	}
+	logger.Log.Infof("the artifact %s is verified, %d files are checked", v.KubeConf.Arg.Artifact, len(index.Files))
+	return nil
+}
+
+// GetOSRelease reads the /etc/os-release of the host into the host cache.
+type GetOSRelease struct {
+	common.KubeAction
+}
+
+func (g *GetOSRelease) Execute(runtime connector.Runtime) error {
+	out, err := runtime.GetRunner().Cmd("cat /etc/os-release", false)
+	if err != nil {
+		return errors.Wrap(err, "get os release failed")
+	}
+	runtime.RemoteHost().GetCache().Set(OSRelease, osrelease.Parse(strings.Replace(out, "\r\n", "\n", -1)))
+	return nil
+}
+
 type UnArchive struct {
 	common.KubeAction
 }
 
 func (u *UnArchive) Execute(runtime connector.Runtime) error {
+	delta, err := ReadDeltaManifest(u.KubeConf.Arg.Artifact)
+	if err != nil {
+		return err
+	}
+	if delta != nil {
+		return MergeDelta(u.KubeConf.Arg.Artifact, runtime.GetWorkDir(), delta)
+	}
+
 	if err := coreutil.Untar(u.KubeConf.Arg.Artifact, runtime.GetWorkDir()); err != nil {
 		return errors.Wrapf(errors.WithStack(err), "unArchive %s failed", u.KubeConf.Arg.Artifact)
 	}
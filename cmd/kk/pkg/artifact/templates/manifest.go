@@ -44,8 +44,8 @@ spec:
     osImage: {{ $v.OsImage }}
     repository:
       iso:
-        localPath: 
-        url: 
+        localPath: {{ $v.Repository.Iso.LocalPath }}
+        url: {{ $v.Repository.Iso.Url }}
   {{- end }}
   kubernetesDistributions:
   {{- range $i, $v := .Options.KubernetesDistributions }}
@@ -66,6 +66,17 @@ spec:
     {{- end}}
     crictl: 
       version: {{ .Options.Components.Crictl.Version }}
+    {{- if .Options.Components.DockerRegistry.Version }}
+    docker-registry:
+      version: "{{ .Options.Components.DockerRegistry.Version }}"
+    {{- end }}
+    {{- if .Options.Components.Harbor.Version }}
+    harbor:
+      version: {{ .Options.Components.Harbor.Version }}
+    docker-compose:
+      version: {{ .Options.Components.DockerCompose.Version }}
+    {{- end }}
+    {{- if not (or .Options.Components.DockerRegistry.Version .Options.Components.Harbor.Version) }}
     ## 
     # docker-registry:
     #   version: "2"
@@ -73,9 +84,13 @@ spec:
     #   version: v2.4.1
     # docker-compose:
     #   version: v2.2.2
+    {{- end }}
   images:
   {{- range .Options.Images }}
   - {{ . }}
+  {{- end }}
+  {{- if .Options.Addons }}
+{{ .Options.Addons }}
   {{- end }}
   registry:
     auths: {}
@@ -89,6 +104,8 @@ type Options struct {
 	KubernetesDistributions []kubekeyv1alpha2.KubernetesDistribution
 	Components              kubekeyv1alpha2.Components
 	Images                  []string
+	// Addons is the rendered addons block of the spec.
+	Addons string
 }
 
 func RenderManifest(opt *Options) (string, error) {
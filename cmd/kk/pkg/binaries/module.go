@@ -27,6 +27,10 @@ type NodeBinariesModule struct {
 	common.KubeModule
 }
 
+func (n *NodeBinariesModule) IsStateful() bool {
+	return true
+}
+
 func (n *NodeBinariesModule) Init() {
 	n.Name = "NodeBinariesModule"
 	n.Desc = "Download installation binaries"
@@ -46,6 +50,10 @@ type K3sNodeBinariesModule struct {
 	common.KubeModule
 }
 
+func (k *K3sNodeBinariesModule) IsStateful() bool {
+	return true
+}
+
 func (k *K3sNodeBinariesModule) Init() {
 	k.Name = "K3sNodeBinariesModule"
 	k.Desc = "Download installation binaries"
@@ -65,6 +73,10 @@ type K8eNodeBinariesModule struct {
 	common.KubeModule
 }
 
+func (k *K8eNodeBinariesModule) IsStateful() bool {
+	return true
+}
+
 func (k *K8eNodeBinariesModule) Init() {
 	k.Name = "K8eNodeBinariesModule"
 	k.Desc = "Download installation binaries"
@@ -172,6 +184,8 @@ func (i *CriBinariesModule) Init() {
 		i.Tasks = CriBinaries(i)
 	case common.Conatinerd:
 		i.Tasks = CriBinaries(i)
+	case common.Crio:
+		i.Tasks = CriBinaries(i)
 	default:
 	}
 
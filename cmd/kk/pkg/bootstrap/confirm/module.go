@@ -31,6 +31,10 @@ func (i *InstallConfirmModule) IsSkip() bool {
 	return i.Skip
 }
 
+func (i *InstallConfirmModule) IsStateful() bool {
+	return true
+}
+
 func (i *InstallConfirmModule) Init() {
 	i.Name = "ConfirmModule"
 	i.Desc = "Display confirmation form"
@@ -108,6 +112,30 @@ func (u *UpgradeConfirmModule) Init() {
 	}
 }
 
+type RestoreETCDConfirmModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RestoreETCDConfirmModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RestoreETCDConfirmModule) Init() {
+	r.Name = "RestoreETCDConfirmModule"
+	r.Desc = "Display restore etcd confirmation form"
+
+	display := &task.LocalTask{
+		Name:   "ConfirmForm",
+		Desc:   "Display confirmation form",
+		Action: new(RestoreETCDConfirm),
+	}
+
+	r.Tasks = []task.Interface{
+		display,
+	}
+}
+
 type CheckFileExistModule struct {
 	module.BaseTaskModule
 	FileName string
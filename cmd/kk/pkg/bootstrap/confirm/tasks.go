@@ -176,6 +176,36 @@ func (d *DeleteConfirm) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+type RestoreETCDConfirm struct {
+	common.KubeAction
+}
+
+func (r *RestoreETCDConfirm) Execute(runtime connector.Runtime) error {
+	fmt.Printf("The data of etcd will be replaced by the snapshot %s, and kube-apiserver will be stopped during the restore.\n",
+		r.KubeConf.Arg.EtcdSnapshot)
+
+	reader := bufio.NewReader(os.Stdin)
+	confirmOK := false
+	for !confirmOK {
+		fmt.Printf("Are you sure to restore etcd? [yes/no]: ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		input = strings.ToLower(strings.TrimSpace(input))
+
+		switch input {
+		case "yes", "y":
+			confirmOK = true
+		case "no", "n":
+			os.Exit(0)
+		default:
+			continue
+		}
+	}
+	return nil
+}
+
 type UpgradeConfirm struct {
 	common.KubeAction
 }
@@ -51,6 +51,8 @@ func (c *ConfigureOSModule) Init() {
 		Parallel: true,
 	}
 
+	tweaks := c.KubeConf.Cluster.System.Tweaks
+	sysctl := templates.SysctlParams(c.KubeConf)
 	GenerateScript := &task.RemoteTask{
 		Name:  "GenerateScript",
 		Desc:  "Generate init os script",
@@ -59,7 +61,16 @@ func (c *ConfigureOSModule) Init() {
 			Template: templates.InitOsScriptTmpl,
 			Dst:      filepath.Join(common.KubeScriptDir, "initOS.sh"),
 			Data: util.Data{
-				"Hosts": templates.GenerateHosts(c.Runtime, c.KubeConf),
+				"Hosts":           templates.GenerateHosts(c.Runtime, c.KubeConf),
+				"Sysctl":          templates.GenerateSysctl(sysctl),
+				"SysctlKeys":      templates.SysctlKeys(sysctl),
+				"Modules":         templates.GenerateModules(c.KubeConf),
+				"Limits":          templates.GenerateLimits(c.KubeConf),
+				"SwapOff":         tweaks.Enabled(tweaks.SwapOff),
+				"DisableSELinux":  tweaks.Enabled(tweaks.DisableSELinux),
+				"DisableFirewall": tweaks.Enabled(tweaks.DisableFirewall),
+				"DropCaches":      tweaks.Enabled(tweaks.DropCaches),
+				"IptablesLegacy":  tweaks.EnabledExplicitly(tweaks.IptablesLegacy),
 			},
 		},
 		Parallel: true,
@@ -116,6 +127,15 @@ func (c *ClearNodeOSModule) Init() {
 		Parallel: true,
 	}
 
+	resetOSConfig := &task.RemoteTask{
+		Name:     "ResetOSConfig",
+		Desc:     "Remove the sysctl, kernel modules and limits drop-ins",
+		Hosts:    c.Runtime.GetHostsByRole(common.Worker),
+		Prepare:  new(DeleteNode),
+		Action:   new(ResetOSConfig),
+		Parallel: true,
+	}
+
 	daemonReload := &task.RemoteTask{
 		Name:     "DaemonReload",
 		Desc:     "Systemd daemon reload",
@@ -128,6 +148,7 @@ func (c *ClearNodeOSModule) Init() {
 	c.Tasks = []task.Interface{
 		resetNetworkConfig,
 		removeFiles,
+		resetOSConfig,
 		daemonReload,
 	}
 }
@@ -166,6 +187,14 @@ func (c *ClearOSEnvironmentModule) Init() {
 		Parallel: true,
 	}
 
+	resetOSConfig := &task.RemoteTask{
+		Name:     "ResetOSConfig",
+		Desc:     "Remove the sysctl, kernel modules and limits drop-ins",
+		Hosts:    c.Runtime.GetHostsByRole(common.K8s),
+		Action:   new(ResetOSConfig),
+		Parallel: true,
+	}
+
 	daemonReload := &task.RemoteTask{
 		Name:     "DaemonReload",
 		Desc:     "Systemd daemon reload",
@@ -178,6 +207,7 @@ func (c *ClearOSEnvironmentModule) Init() {
 		resetNetworkConfig,
 		uninstallETCD,
 		removeFiles,
+		resetOSConfig,
 		daemonReload,
 	}
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/os/repository"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/os/templates"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils"
@@ -184,6 +185,21 @@ func (r *ResetNetworkConfig) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+type ResetOSConfig struct {
+	common.KubeAction
+}
+
+func (r *ResetOSConfig) Execute(runtime connector.Runtime) error {
+	for _, file := range []string{templates.SysctlDropIn, templates.ModulesDropIn, templates.LimitsDropIn} {
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s", file), true); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "remove %s failed", file)
+		}
+	}
+	// re-apply the remaining parameters, the ones only set by kubekey keep their values until reboot
+	_, _ = runtime.GetRunner().SudoCmd("sysctl --system 1>/dev/null", false)
+	return nil
+}
+
 type UninstallETCD struct {
 	common.KubeAction
 }
@@ -410,6 +426,9 @@ func (i *InstallPackage) Execute(runtime connector.Runtime) error {
 	} else if _, ok := r.(*repository.RedhatPackageManager); ok {
 		pkg = i.KubeConf.Cluster.System.Rpms
 	}
+	if host.IsRole(common.Loadbalancer) {
+		pkg = append([]string{"haproxy", "keepalived"}, pkg...)
+	}
 
 	if installErr := r.Install(runtime, pkg...); installErr != nil {
 		return errors.Wrap(errors.WithStack(installErr), "install repository package failed")
@@ -18,8 +18,11 @@ package templates
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"text/template"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/registry"
 
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
@@ -44,8 +47,13 @@ var InitOsScriptTmpl = template.Must(template.New("initOS.sh").Parse(
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
+{{- if .SwapOff }}
+
 swapoff -a
 sed -i /^[^#]*swap*/s/^/\#/g /etc/fstab
+{{- end }}
+
+{{- if .DisableSELinux }}
 
 # See https://github.com/kubernetes/website/issues/14457
 if [ -f /etc/selinux/config ]; then 
@@ -58,73 +66,51 @@ then
   setenforce 0
   getenforce
 fi
+{{- end }}
 
-echo 'net.ipv4.ip_forward = 1' >> /etc/sysctl.conf
-echo 'net.bridge.bridge-nf-call-arptables = 1' >> /etc/sysctl.conf
-echo 'net.bridge.bridge-nf-call-ip6tables = 1' >> /etc/sysctl.conf
-echo 'net.bridge.bridge-nf-call-iptables = 1' >> /etc/sysctl.conf
-echo 'net.ipv4.ip_local_reserved_ports = 30000-32767' >> /etc/sysctl.conf
-echo 'vm.max_map_count = 262144' >> /etc/sysctl.conf
-echo 'vm.swappiness = 1' >> /etc/sysctl.conf
-echo 'fs.inotify.max_user_instances = 524288' >> /etc/sysctl.conf
-echo 'kernel.pid_max = 65535' >> /etc/sysctl.conf
-
-
-#See https://imroc.io/posts/kubernetes/troubleshooting-with-kubernetes-network/
-sed -r -i "s@#{0,}?net.ipv4.tcp_tw_recycle ?= ?(0|1)@net.ipv4.tcp_tw_recycle = 0@g" /etc/sysctl.conf
-
-sed -r -i  "s@#{0,}?net.ipv4.ip_forward ?= ?(0|1)@net.ipv4.ip_forward = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-arptables ?= ?(0|1)@net.bridge.bridge-nf-call-arptables = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-ip6tables ?= ?(0|1)@net.bridge.bridge-nf-call-ip6tables = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-iptables ?= ?(0|1)@net.bridge.bridge-nf-call-iptables = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.ipv4.ip_local_reserved_ports ?= ?([0-9]{1,}-{0,1},{0,1}){1,}@net.ipv4.ip_local_reserved_ports = 30000-32767@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?vm.max_map_count ?= ?([0-9]{1,})@vm.max_map_count = 262144@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?vm.swappiness ?= ?([0-9]{1,})@vm.swappiness = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?fs.inotify.max_user_instances ?= ?([0-9]{1,})@fs.inotify.max_user_instances = 524288@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?kernel.pid_max ?= ?([0-9]{1,})@kernel.pid_max = 65535@g" /etc/sysctl.conf
-
-tmpfile="$$.tmp"
-awk ' !x[$0]++{print > "'$tmpfile'"}' /etc/sysctl.conf
-mv $tmpfile /etc/sysctl.conf
+{{- if .DisableFirewall }}
 
 systemctl stop firewalld 1>/dev/null 2>/dev/null
 systemctl disable firewalld 1>/dev/null 2>/dev/null
 systemctl stop ufw 1>/dev/null 2>/dev/null
 systemctl disable ufw 1>/dev/null 2>/dev/null
+{{- end }}
 
-modinfo br_netfilter > /dev/null 2>&1
-if [ $? -eq 0 ]; then
-   modprobe br_netfilter
-   mkdir -p /etc/modules-load.d
-   echo 'br_netfilter' > /etc/modules-load.d/kubekey-br_netfilter.conf
-fi
-
-modinfo overlay > /dev/null 2>&1
-if [ $? -eq 0 ]; then
-   modprobe overlay
-   echo 'overlay' >> /etc/modules-load.d/kubekey-br_netfilter.conf
-fi
+# Drop-ins written by former versions of kubekey.
+rm -f /etc/modules-load.d/kubekey-br_netfilter.conf /etc/modules-load.d/kube_proxy-ipvs.conf
+
+# Only the modules can be loaded are written, otherwise systemd-modules-load fails on boot.
+mkdir -p /etc/modules-load.d
+: > /etc/modules-load.d/kubekey.conf
+for module in {{ range .Modules }}{{ . }} {{ end }}; do
+  if modprobe $module 1>/dev/null 2>/dev/null; then
+    echo $module >> /etc/modules-load.d/kubekey.conf
+  fi
+done
+
+# /etc/sysctl.conf is applied after the drop-ins by 'sysctl --system',
+# remove the parameters managed here which were appended to it by former versions of kubekey.
+{{- range .SysctlKeys }}
+sed -r -i '/^[[:space:]]*#?[[:space:]]*{{ . }}[[:space:]]*=/d' /etc/sysctl.conf
+{{- end }}
 
-modprobe ip_vs
-modprobe ip_vs_rr
-modprobe ip_vs_wrr
-modprobe ip_vs_sh
+#See https://imroc.io/posts/kubernetes/troubleshooting-with-kubernetes-network/
+sed -r -i "s@#{0,}?net.ipv4.tcp_tw_recycle ?= ?(0|1)@net.ipv4.tcp_tw_recycle = 0@g" /etc/sysctl.conf
 
-cat > /etc/modules-load.d/kube_proxy-ipvs.conf << EOF
-ip_vs
-ip_vs_rr
-ip_vs_wrr
-ip_vs_sh
+mkdir -p /etc/sysctl.d
+cat > /etc/sysctl.d/99-kubekey.conf << 'EOF'
+{{- range .Sysctl }}
+{{ . }}
+{{- end }}
 EOF
+sysctl --system 1>/dev/null
 
-modprobe nf_conntrack_ipv4 1>/dev/null 2>/dev/null
-if [ $? -eq 0 ]; then
-   echo 'nf_conntrack_ipv4' > /etc/modules-load.d/kube_proxy-ipvs.conf
-else
-   modprobe nf_conntrack
-   echo 'nf_conntrack' > /etc/modules-load.d/kube_proxy-ipvs.conf
-fi
-sysctl -p
+mkdir -p /etc/security/limits.d
+cat > /etc/security/limits.d/99-kubekey.conf << 'EOF'
+{{- range .Limits }}
+{{ . }}
+{{- end }}
+EOF
 
 sed -i ':a;$!{N;ba};s@# kubekey hosts BEGIN.*# kubekey hosts END@@' /etc/hosts
 sed -i '/^$/N;/\n$/N;//D' /etc/hosts
@@ -137,16 +123,19 @@ cat >>/etc/hosts<<EOF
 # kubekey hosts END
 EOF
 
+{{- if .DropCaches }}
+
 echo 3 > /proc/sys/vm/drop_caches
+{{- end }}
+
+{{- if .IptablesLegacy }}
 
 # Make sure the iptables utility doesn't use the nftables backend.
 update-alternatives --set iptables /usr/sbin/iptables-legacy >/dev/null 2>&1 || true
 update-alternatives --set ip6tables /usr/sbin/ip6tables-legacy >/dev/null 2>&1 || true
 update-alternatives --set arptables /usr/sbin/arptables-legacy >/dev/null 2>&1 || true
 update-alternatives --set ebtables /usr/sbin/ebtables-legacy >/dev/null 2>&1 || true
-
-ulimit -u 65535
-ulimit -n 65535
+{{- end }}
 
     `)))
 
@@ -177,3 +166,112 @@ func GenerateHosts(runtime connector.ModuleRuntime, kubeConf *common.KubeConf) [
 	hostsList = append(hostsList, lbHost)
 	return hostsList
 }
+
+const (
+	SysctlDropIn  = "/etc/sysctl.d/99-kubekey.conf"
+	ModulesDropIn = "/etc/modules-load.d/kubekey.conf"
+	LimitsDropIn  = "/etc/security/limits.d/99-kubekey.conf"
+)
+
+// defaultSysctl are the kernel parameters required by kubernetes and its addons.
+var defaultSysctl = map[string]string{
+	"net.ipv4.ip_forward":                 "1",
+	"net.bridge.bridge-nf-call-arptables": "1",
+	"net.bridge.bridge-nf-call-ip6tables": "1",
+	"net.bridge.bridge-nf-call-iptables":  "1",
+	"net.ipv4.ip_local_reserved_ports":    "30000-32767",
+	"vm.max_map_count":                    "262144",
+	"vm.swappiness":                       "1",
+	"fs.inotify.max_user_instances":       "524288",
+	"kernel.pid_max":                      "65535",
+}
+
+// defaultModules are required by the container runtime, the bridge sysctls and kube-proxy in ipvs mode.
+// nf_conntrack_ipv4 only exists on kernels older than 4.19.
+var defaultModules = []string{"br_netfilter", "overlay", "ip_vs", "ip_vs_rr", "ip_vs_wrr", "ip_vs_sh", "nf_conntrack_ipv4", "nf_conntrack"}
+
+var defaultLimits = []kubekeyapiv1alpha2.Limit{
+	{Domain: "*", Type: "soft", Item: "nofile", Value: "65535"},
+	{Domain: "*", Type: "hard", Item: "nofile", Value: "65535"},
+	{Domain: "*", Type: "soft", Item: "nproc", Value: "65535"},
+	{Domain: "*", Type: "hard", Item: "nproc", Value: "65535"},
+}
+
+// SysctlParams merges the built-in kernel parameters with the ones of the cluster config.
+func SysctlParams(kubeConf *common.KubeConf) map[string]string {
+	system := kubeConf.Cluster.System
+	params := make(map[string]string)
+	if system.Tweaks.Enabled(system.Tweaks.KernelParams) {
+		for k, v := range defaultSysctl {
+			params[k] = v
+		}
+	}
+	for k, v := range system.Sysctl {
+		params[k] = v
+	}
+	return params
+}
+
+func GenerateSysctl(params map[string]string) []string {
+	lines := make([]string, 0, len(params))
+	for _, k := range sortedKeys(params) {
+		lines = append(lines, fmt.Sprintf("%s = %s", k, params[k]))
+	}
+	return lines
+}
+
+// SysctlKeys returns the names of the parameters escaped for a sed pattern.
+func SysctlKeys(params map[string]string) []string {
+	keys := sortedKeys(params)
+	for i := range keys {
+		keys[i] = strings.ReplaceAll(keys[i], ".", `\.`)
+	}
+	return keys
+}
+
+func GenerateModules(kubeConf *common.KubeConf) []string {
+	system := kubeConf.Cluster.System
+	var modules []string
+	if system.Tweaks.Enabled(system.Tweaks.KernelModules) {
+		modules = append(modules, defaultModules...)
+	}
+	for _, m := range system.Modules {
+		if !contains(modules, m) {
+			modules = append(modules, m)
+		}
+	}
+	return modules
+}
+
+func GenerateLimits(kubeConf *common.KubeConf) []string {
+	system := kubeConf.Cluster.System
+	var limits []kubekeyapiv1alpha2.Limit
+	if system.Tweaks.EnabledExplicitly(system.Tweaks.Limits) {
+		limits = append(limits, defaultLimits...)
+	}
+	limits = append(limits, system.Limits...)
+
+	lines := make([]string, 0, len(limits))
+	for _, l := range limits {
+		lines = append(lines, fmt.Sprintf("%s %s %s %s", l.Domain, l.Type, l.Item, l.Value))
+	}
+	return lines
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
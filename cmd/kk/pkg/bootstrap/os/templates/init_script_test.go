@@ -0,0 +1,71 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+func TestInitOsScriptTweaks(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name   string
+		tweaks kubekeyapiv1alpha2.SystemTweaks
+		want   bool
+	}{
+		{
+			name: "default",
+			want: false,
+		},
+		{
+			name:   "enabled",
+			tweaks: kubekeyapiv1alpha2.SystemTweaks{Limits: &enabled, IptablesLegacy: &enabled},
+			want:   true,
+		},
+		{
+			name:   "disabled",
+			tweaks: kubekeyapiv1alpha2.SystemTweaks{Limits: &disabled, IptablesLegacy: &disabled},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeConf := &common.KubeConf{Cluster: &kubekeyapiv1alpha2.ClusterSpec{
+				System: kubekeyapiv1alpha2.System{Tweaks: tt.tweaks},
+			}}
+			tweaks := kubeConf.Cluster.System.Tweaks
+			script, err := util.Render(InitOsScriptTmpl, util.Data{
+				"Limits":         GenerateLimits(kubeConf),
+				"IptablesLegacy": tweaks.EnabledExplicitly(tweaks.IptablesLegacy),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(script, "* soft nofile 65535"); got != tt.want {
+				t.Errorf("limits rendered = %v, want %v", got, tt.want)
+			}
+			if got := strings.Contains(script, "iptables-legacy"); got != tt.want {
+				t.Errorf("iptables-legacy rendered = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
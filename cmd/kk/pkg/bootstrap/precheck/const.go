@@ -16,6 +16,8 @@
 
 package precheck
 
+import "time"
+
 const (
 	// command software
 	sudo       = "sudo"
@@ -57,3 +59,36 @@ var baseSoftware = []string{
 	rbd,
 	glusterfs,
 }
+
+const (
+	// the free space of the directories hold the data
+	minFreeDiskMiB = 5 * 1024
+	recFreeDiskMiB = 20 * 1024
+
+	minKernelVersion     = "3.10"
+	recIPVSKernelVersion = "4.19"
+
+	// the clock of a node differs from the others
+	recTimeSkew = 2 * time.Second
+	maxTimeSkew = 5 * time.Minute
+)
+
+// kubeProcesses are allowed to listen on the required ports, e.g. on the nodes of a running cluster
+// or the internal load balancer of the control plane. ss truncates the names to 15 characters.
+// k3s and k8e rename their process after the role they run, e.g. k3s-server.
+var kubeProcesses = map[string]bool{
+	"kube-apiserver":  true,
+	"kube-controller": true,
+	"kube-scheduler":  true,
+	"kubelet":         true,
+	"etcd":            true,
+	"k3s":             true,
+	"k3s-server":      true,
+	"k3s-agent":       true,
+	"k8e":             true,
+	"k8e-server":      true,
+	"k8e-agent":       true,
+	"haproxy":         true,
+	"nginx":           true,
+	"envoy":           true,
+}
@@ -61,6 +61,10 @@ func (n *NodePreCheckModule) IsSkip() bool {
 	return n.Skip
 }
 
+func (n *NodePreCheckModule) IsStateful() bool {
+	return true
+}
+
 func (n *NodePreCheckModule) Init() {
 	n.Name = "NodePreCheckModule"
 	n.Desc = "Do pre-check on cluster nodes"
@@ -81,8 +85,24 @@ func (n *NodePreCheckModule) Init() {
 		Parallel: true,
 	}
 
+	preflightCheck := &task.RemoteTask{
+		Name:     "NodePreflightCheck",
+		Desc:     "Gather the facts of nodes for the pre-flight checks",
+		Hosts:    n.Runtime.GetAllHosts(),
+		Action:   new(NodePreflightCheck),
+		Parallel: true,
+	}
+
+	preflightReport := &task.LocalTask{
+		Name:   "PreflightReport",
+		Desc:   "Check the resources, ports, kernel, time and network of nodes",
+		Action: new(PreflightReport),
+	}
+
 	n.Tasks = []task.Interface{
 		preCheck,
+		preflightCheck,
+		preflightReport,
 	}
 }
 
@@ -0,0 +1,288 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package precheck
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modood/table"
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+)
+
+// NodeFacts are gathered from a node by NodePreflightCheck and evaluated by PreflightReport.
+// A zero value means the fact could not be gathered.
+type NodeFacts struct {
+	Name        string
+	CPU         int
+	MemoryMiB   int
+	Kernel      string
+	MACs        []string
+	ProductUUID string
+	Swap        bool
+	// TimeOffset is the difference between the clock of the node and the clock of the local machine.
+	TimeOffset *time.Duration
+	// FreeDiskMiB is the free space of the filesystem which holds the path.
+	FreeDiskMiB map[string]int
+	// Listening maps the listening tcp ports to the name of the process, nil if ss is not available.
+	Listening map[int]string
+	Routes    []Route
+	// Joined is true if the node is already a member of a cluster, e.g. when it is upgraded.
+	Joined bool
+}
+
+// Route is a route of the node, Dst is a CIDR.
+type Route struct {
+	Dst string
+	Dev string
+}
+
+type NodePreflightCheck struct {
+	common.KubeAction
+}
+
+func (n *NodePreflightCheck) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	facts := &NodeFacts{
+		Name:        host.GetName(),
+		FreeDiskMiB: make(map[string]int),
+	}
+
+	if out, err := runtime.GetRunner().Cmd("nproc", false); err == nil {
+		facts.CPU, _ = strconv.Atoi(strings.TrimSpace(out))
+	}
+	if out, err := runtime.GetRunner().Cmd("grep MemTotal /proc/meminfo", false); err == nil {
+		facts.MemoryMiB = parseMemTotal(out)
+	}
+	if out, err := runtime.GetRunner().Cmd("uname -r", false); err == nil {
+		facts.Kernel = strings.TrimSpace(out)
+	}
+	// only the interfaces backed by a device, the virtual ones may share the same address on every node
+	if out, err := runtime.GetRunner().Cmd("for dev in /sys/class/net/*; do [ -e $dev/device ] && cat $dev/address; done; true", false); err == nil {
+		facts.MACs = strings.Fields(out)
+	}
+	if out, err := runtime.GetRunner().SudoCmd("cat /sys/class/dmi/id/product_uuid", false); err == nil {
+		facts.ProductUUID = strings.ToLower(strings.TrimSpace(out))
+	}
+	if out, err := runtime.GetRunner().Cmd("tail -n +2 /proc/swaps", false); err == nil {
+		facts.Swap = strings.TrimSpace(out) != ""
+	}
+
+	before := time.Now()
+	if out, err := runtime.GetRunner().Cmd("date +%s", false); err == nil {
+		if sec, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64); err == nil {
+			local := before.Add(time.Since(before) / 2)
+			offset := time.Unix(sec, 0).Sub(local.Truncate(time.Second))
+			facts.TimeOffset = &offset
+		}
+	}
+
+	for _, path := range diskPaths(n.KubeConf) {
+		if free, ok := freeDisk(runtime, path); ok {
+			facts.FreeDiskMiB[path] = free
+		}
+	}
+
+	if out, err := runtime.GetRunner().SudoCmd("ss -tlnp", false); err == nil {
+		facts.Listening = parseListening(out)
+	}
+	if out, err := runtime.GetRunner().Cmd("ip -4 route show; ip -6 route show", false); err == nil {
+		facts.Routes = parseRoutes(out)
+	}
+	if _, err := runtime.GetRunner().SudoCmd("test -f /etc/kubernetes/kubelet.conf -o -d /var/lib/rancher/k3s/agent -o -d /var/lib/rancher/k8e/agent", false); err == nil {
+		facts.Joined = true
+	}
+
+	host.GetCache().Set(common.NodePreflightFacts, facts)
+	return nil
+}
+
+// freeDisk returns the free space of the filesystem holds the path, the path may not be created yet.
+func freeDisk(runtime connector.Runtime, path string) (int, bool) {
+	for p := path; ; p = parentDir(p) {
+		out, err := runtime.GetRunner().Cmd(fmt.Sprintf("df -Pm %s", p), false)
+		if err == nil {
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			fields := strings.Fields(lines[len(lines)-1])
+			if len(fields) >= 4 {
+				if free, err := strconv.Atoi(fields[3]); err == nil {
+					return free, true
+				}
+			}
+			return 0, false
+		}
+		if p == "/" {
+			return 0, false
+		}
+	}
+}
+
+func parentDir(path string) string {
+	i := strings.LastIndex(strings.TrimSuffix(path, "/"), "/")
+	if i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+func parseMemTotal(out string) int {
+	fields := strings.Fields(out)
+	if len(fields) < 2 {
+		return 0
+	}
+	kb, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0
+	}
+	return kb / 1024
+}
+
+var ssProcess = regexp.MustCompile(`users:\(\("([^"]+)"`)
+
+// parseListening parses the output of 'ss -tlnp'.
+func parseListening(out string) map[int]string {
+	listening := make(map[int]string)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] == "State" {
+			continue
+		}
+		local := fields[3]
+		port, err := strconv.Atoi(local[strings.LastIndex(local, ":")+1:])
+		if err != nil {
+			continue
+		}
+		process := ""
+		if m := ssProcess.FindStringSubmatch(line); m != nil {
+			process = m[1]
+		}
+		listening[port] = process
+	}
+	return listening
+}
+
+// parseRoutes parses the output of 'ip route show', the blackhole, unreachable and prohibit routes are skipped.
+func parseRoutes(out string) []Route {
+	var routes []Route
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "default" {
+			continue
+		}
+		switch fields[0] {
+		case "blackhole", "unreachable", "prohibit", "throw", "local", "broadcast", "multicast":
+			continue
+		}
+		route := Route{Dst: fields[0]}
+		if !strings.Contains(route.Dst, "/") {
+			if strings.Contains(route.Dst, ":") {
+				route.Dst += "/128"
+			} else {
+				route.Dst += "/32"
+			}
+		}
+		for i := 1; i < len(fields)-1; i++ {
+			if fields[i] == "dev" {
+				route.Dev = fields[i+1]
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+type PreflightReport struct {
+	common.KubeAction
+}
+
+func (p *PreflightReport) Execute(runtime connector.Runtime) error {
+	hosts := runtime.GetAllHosts()
+	facts := make([]*NodeFacts, 0, len(hosts))
+	var results []CheckResult
+	for _, host := range hosts {
+		v, ok := host.GetCache().Get(common.NodePreflightFacts)
+		if !ok {
+			return errors.Errorf("get node %s pre-flight facts failed by host cache", host.GetName())
+		}
+		f := v.(*NodeFacts)
+		facts = append(facts, f)
+		for _, check := range nodeChecks {
+			results = append(results, check(host, f, p.KubeConf)...)
+		}
+	}
+	for _, check := range clusterChecks {
+		results = append(results, check(facts, p.KubeConf)...)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Node < results[j].Node
+	})
+
+	table.OutputA(results)
+	fmt.Println()
+	p.PipelineCache.Set(common.PreflightResults, results)
+
+	var (
+		failed       []string
+		failedChecks []string
+	)
+	for _, r := range results {
+		switch r.Status {
+		case CheckWarn:
+			logger.Log.Warnf("%s: %s", r.Node, r.Message)
+		case CheckFail:
+			if ignorePreflightError(p.KubeConf.Arg.IgnorePreflightErrors, r.Check) {
+				logger.Log.Warnf("%s: %s (ignored)", r.Node, r.Message)
+				continue
+			}
+			failed = append(failed, fmt.Sprintf("%s: %s", r.Node, r.Message))
+			if !contains(failedChecks, r.Check) {
+				failedChecks = append(failedChecks, r.Check)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("pre-flight checks failed:\n%s\nuse --ignore-preflight-errors to skip the checks, e.g. --ignore-preflight-errors=%s",
+			strings.Join(failed, "\n"), strings.Join(failedChecks, ","))
+	}
+	return nil
+}
+
+func ignorePreflightError(ignored []string, check string) bool {
+	for _, i := range ignored {
+		if strings.EqualFold(i, "all") || strings.EqualFold(i, check) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,370 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package precheck
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+)
+
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// CheckResult is a row of the pre-flight report.
+type CheckResult struct {
+	Node    string      `table:"node"`
+	Check   string      `table:"check"`
+	Status  CheckStatus `table:"status"`
+	Message string      `table:"message"`
+}
+
+type nodeCheck func(host connector.Host, facts *NodeFacts, kubeConf *common.KubeConf) []CheckResult
+
+type clusterCheck func(facts []*NodeFacts, kubeConf *common.KubeConf) []CheckResult
+
+// nodeChecks are evaluated against the facts of every node.
+var nodeChecks = []nodeCheck{
+	checkResources,
+	checkDiskSpace,
+	checkPorts,
+	checkSwap,
+	checkKernel,
+	checkCIDR,
+}
+
+// clusterChecks are evaluated against the facts of all nodes.
+var clusterChecks = []clusterCheck{
+	checkTimeSkew,
+	// the nodes are renamed after their configured names by ConfigureOSModule, hostnames are case-insensitive
+	checkUnique("Hostname", "hostname", func(f *NodeFacts) []string { return []string{strings.ToLower(f.Name)} }),
+	checkUnique("MAC", "MAC address", func(f *NodeFacts) []string { return f.MACs }),
+	checkUnique("ProductUUID", "product_uuid", func(f *NodeFacts) []string { return []string{f.ProductUUID} }),
+}
+
+func result(node, check string, status CheckStatus, format string, args ...interface{}) CheckResult {
+	return CheckResult{Node: node, Check: check, Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+type resourceRequirement struct {
+	minCPU, minMemoryMiB int
+	recCPU, recMemoryMiB int
+}
+
+// resourceRequirements are the minimum and recommended resources of each role, the minimums of
+// the control plane are the ones checked by kubeadm.
+var resourceRequirements = map[string]resourceRequirement{
+	common.Master: {minCPU: 2, minMemoryMiB: 1700, recCPU: 4, recMemoryMiB: 8192},
+	common.ETCD:   {recCPU: 2, recMemoryMiB: 2048},
+	common.Worker: {minCPU: 1, minMemoryMiB: 1024, recCPU: 2, recMemoryMiB: 4096},
+}
+
+func checkResources(host connector.Host, facts *NodeFacts, _ *common.KubeConf) []CheckResult {
+	var req resourceRequirement
+	for role, r := range resourceRequirements {
+		if !host.IsRole(role) {
+			continue
+		}
+		req.minCPU, req.minMemoryMiB = max(req.minCPU, r.minCPU), max(req.minMemoryMiB, r.minMemoryMiB)
+		req.recCPU, req.recMemoryMiB = max(req.recCPU, r.recCPU), max(req.recMemoryMiB, r.recMemoryMiB)
+	}
+
+	var results []CheckResult
+	switch {
+	case facts.CPU == 0:
+		results = append(results, result(facts.Name, "CPU", CheckWarn, "unable to get the number of CPUs"))
+	case facts.CPU < req.minCPU:
+		results = append(results, result(facts.Name, "CPU", CheckFail, "%d CPUs is less than the minimum %d", facts.CPU, req.minCPU))
+	case facts.CPU < req.recCPU:
+		results = append(results, result(facts.Name, "CPU", CheckWarn, "%d CPUs is less than the recommended %d", facts.CPU, req.recCPU))
+	default:
+		results = append(results, result(facts.Name, "CPU", CheckPass, "%d CPUs", facts.CPU))
+	}
+
+	switch {
+	case facts.MemoryMiB == 0:
+		results = append(results, result(facts.Name, "Memory", CheckWarn, "unable to get the memory size"))
+	case facts.MemoryMiB < req.minMemoryMiB:
+		results = append(results, result(facts.Name, "Memory", CheckFail, "%dMi memory is less than the minimum %dMi", facts.MemoryMiB, req.minMemoryMiB))
+	case facts.MemoryMiB < req.recMemoryMiB:
+		results = append(results, result(facts.Name, "Memory", CheckWarn, "%dMi memory is less than the recommended %dMi", facts.MemoryMiB, req.recMemoryMiB))
+	default:
+		results = append(results, result(facts.Name, "Memory", CheckPass, "%dMi memory", facts.MemoryMiB))
+	}
+	return results
+}
+
+// diskPaths are the directories hold the data of kubelet, etcd and the container runtime.
+func diskPaths(kubeConf *common.KubeConf) []string {
+	paths := []string{"/var/lib"}
+	if dataRoot := kubeConf.Cluster.Registry.DataRoot; dataRoot != "" && !strings.HasPrefix(dataRoot, "/var/lib/") {
+		paths = append(paths, dataRoot)
+	}
+	return paths
+}
+
+func checkDiskSpace(_ connector.Host, facts *NodeFacts, kubeConf *common.KubeConf) []CheckResult {
+	var results []CheckResult
+	for _, path := range diskPaths(kubeConf) {
+		free, ok := facts.FreeDiskMiB[path]
+		switch {
+		case !ok:
+			results = append(results, result(facts.Name, "DiskSpace", CheckWarn, "unable to get the free space of %s", path))
+		case free < minFreeDiskMiB:
+			results = append(results, result(facts.Name, "DiskSpace", CheckFail, "%dMi free on %s is less than the minimum %dMi", free, path, minFreeDiskMiB))
+		case free < recFreeDiskMiB:
+			results = append(results, result(facts.Name, "DiskSpace", CheckWarn, "%dMi free on %s is less than the recommended %dMi", free, path, recFreeDiskMiB))
+		default:
+			results = append(results, result(facts.Name, "DiskSpace", CheckPass, "%dMi free on %s", free, path))
+		}
+	}
+	return results
+}
+
+// requiredPorts returns the ports must be free or be used by kubernetes itself on the host.
+func requiredPorts(host connector.Host) []int {
+	var ports []int
+	if host.IsRole(common.Master) {
+		ports = append(ports, kubekeyapiv1alpha2.DefaultApiserverPort, 10257, 10259)
+	}
+	if host.IsRole(common.ETCD) {
+		ports = append(ports, 2379, 2380)
+	}
+	if host.IsRole(common.K8s) {
+		ports = append(ports, 10250)
+	}
+	return ports
+}
+
+func checkPorts(host connector.Host, facts *NodeFacts, _ *common.KubeConf) []CheckResult {
+	ports := requiredPorts(host)
+	if len(ports) == 0 {
+		return nil
+	}
+	if facts.Listening == nil {
+		return []CheckResult{result(facts.Name, "Ports", CheckWarn, "unable to list the listening ports, ss is required")}
+	}
+
+	var used []string
+	for _, port := range ports {
+		process, ok := facts.Listening[port]
+		if !ok || kubeProcesses[process] {
+			continue
+		}
+		if process == "" {
+			process = "unknown process"
+		}
+		used = append(used, fmt.Sprintf("%d (%s)", port, process))
+	}
+	if len(used) > 0 {
+		return []CheckResult{result(facts.Name, "Ports", CheckFail, "ports in use: %s", strings.Join(used, ", "))}
+	}
+	return []CheckResult{result(facts.Name, "Ports", CheckPass, "ports %s are available", joinInts(ports))}
+}
+
+func checkSwap(_ connector.Host, facts *NodeFacts, kubeConf *common.KubeConf) []CheckResult {
+	tweaks := kubeConf.Cluster.System.Tweaks
+	switch {
+	case !facts.Swap:
+		return []CheckResult{result(facts.Name, "Swap", CheckPass, "swap is off")}
+	case tweaks.Enabled(tweaks.SwapOff):
+		return []CheckResult{result(facts.Name, "Swap", CheckPass, "swap is on, it will be turned off")}
+	default:
+		return []CheckResult{result(facts.Name, "Swap", CheckFail, "swap is on and system.tweaks.swapOff is disabled, kubelet does not run with swap")}
+	}
+}
+
+func checkKernel(_ connector.Host, facts *NodeFacts, kubeConf *common.KubeConf) []CheckResult {
+	kernel, err := versionutil.ParseGeneric(facts.Kernel)
+	switch {
+	case err != nil:
+		return []CheckResult{result(facts.Name, "KernelVersion", CheckWarn, "unable to parse the kernel version %q", facts.Kernel)}
+	case kernel.LessThan(versionutil.MustParseGeneric(minKernelVersion)):
+		return []CheckResult{result(facts.Name, "KernelVersion", CheckFail, "kernel %s is older than the minimum %s", facts.Kernel, minKernelVersion)}
+	case kubeConf.Cluster.Kubernetes.ProxyMode == "ipvs" && kernel.LessThan(versionutil.MustParseGeneric(recIPVSKernelVersion)):
+		return []CheckResult{result(facts.Name, "KernelVersion", CheckWarn, "kernel %s is older than %s recommended for kube-proxy in ipvs mode", facts.Kernel, recIPVSKernelVersion)}
+	default:
+		return []CheckResult{result(facts.Name, "KernelVersion", CheckPass, "kernel %s", facts.Kernel)}
+	}
+}
+
+// cniDevices are the prefixes of the devices created by the network plugins and kube-proxy,
+// their routes are made from the pod and service CIDRs.
+var cniDevices = []string{"cali", "tunl", "flannel", "cni", "vxlan", "kube-ipvs", "kube-bridge", "cilium", "lxc", "genev", "ovn", "nodelocaldns"}
+
+func checkCIDR(host connector.Host, facts *NodeFacts, kubeConf *common.KubeConf) []CheckResult {
+	if !host.IsRole(common.K8s) {
+		return nil
+	}
+
+	type named struct {
+		name string
+		cidr *net.IPNet
+	}
+	var cidrs []named
+	for name, value := range map[string]string{
+		"pod":     kubeConf.Cluster.Network.KubePodsCIDR,
+		"service": kubeConf.Cluster.Network.KubeServiceCIDR,
+	} {
+		for _, c := range strings.Split(value, ",") {
+			if _, cidr, err := net.ParseCIDR(strings.TrimSpace(c)); err == nil {
+				cidrs = append(cidrs, named{name: name, cidr: cidr})
+			}
+		}
+	}
+	sort.Slice(cidrs, func(i, j int) bool { return cidrs[i].name < cidrs[j].name })
+
+	var conflicts []string
+	for _, c := range cidrs {
+		if ip := net.ParseIP(host.GetInternalAddress()); ip != nil && c.cidr.Contains(ip) {
+			conflicts = append(conflicts, fmt.Sprintf("%s CIDR %s contains the node address %s", c.name, c.cidr, ip))
+		}
+		for _, route := range facts.Routes {
+			if isCNIDevice(route.Dev) {
+				continue
+			}
+			_, dst, err := net.ParseCIDR(route.Dst)
+			if err != nil {
+				continue
+			}
+			if dst.Contains(c.cidr.IP) || c.cidr.Contains(dst.IP) {
+				conflicts = append(conflicts, fmt.Sprintf("%s CIDR %s overlaps the route %s dev %s", c.name, c.cidr, route.Dst, route.Dev))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		// the routes of a running cluster may be made by a plugin without a device of its own, e.g. bgp or host-gw
+		status := CheckFail
+		if facts.Joined {
+			status = CheckWarn
+		}
+		return []CheckResult{result(facts.Name, "CIDR", status, "%s", strings.Join(conflicts, "; "))}
+	}
+	return []CheckResult{result(facts.Name, "CIDR", CheckPass, "pod and service CIDRs do not overlap the host routes")}
+}
+
+func isCNIDevice(dev string) bool {
+	for _, prefix := range cniDevices {
+		if strings.HasPrefix(dev, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTimeSkew(facts []*NodeFacts, _ *common.KubeConf) []CheckResult {
+	var offsets []time.Duration
+	for _, f := range facts {
+		if f.TimeOffset != nil {
+			offsets = append(offsets, *f.TimeOffset)
+		}
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	median := offsets[len(offsets)/2]
+
+	results := make([]CheckResult, 0, len(facts))
+	for _, f := range facts {
+		if f.TimeOffset == nil {
+			results = append(results, result(f.Name, "TimeSkew", CheckWarn, "unable to get the time"))
+			continue
+		}
+		skew := *f.TimeOffset - median
+		if skew < 0 {
+			skew = -skew
+		}
+		switch {
+		case skew > maxTimeSkew:
+			results = append(results, result(f.Name, "TimeSkew", CheckFail, "clock differs by %s from the other nodes, more than %s", skew, maxTimeSkew))
+		case skew > recTimeSkew:
+			results = append(results, result(f.Name, "TimeSkew", CheckWarn, "clock differs by %s from the other nodes, more than %s", skew, recTimeSkew))
+		default:
+			results = append(results, result(f.Name, "TimeSkew", CheckPass, "clock differs by %s from the other nodes", skew))
+		}
+	}
+	return results
+}
+
+// checkUnique returns a check fails the nodes share a value with another node, e.g. the same hostname.
+func checkUnique(check, desc string, values func(f *NodeFacts) []string) clusterCheck {
+	return func(facts []*NodeFacts, _ *common.KubeConf) []CheckResult {
+		owners := make(map[string][]string)
+		for _, f := range facts {
+			for _, v := range values(f) {
+				if v != "" && !contains(owners[v], f.Name) {
+					owners[v] = append(owners[v], f.Name)
+				}
+			}
+		}
+
+		results := make([]CheckResult, 0, len(facts))
+		for _, f := range facts {
+			var duplicated []string
+			known := false
+			for _, v := range values(f) {
+				if v == "" {
+					continue
+				}
+				known = true
+				for _, owner := range owners[v] {
+					if owner != f.Name {
+						duplicated = append(duplicated, fmt.Sprintf("%s is also used by %s", v, owner))
+					}
+				}
+			}
+			switch {
+			case len(duplicated) > 0:
+				results = append(results, result(f.Name, check, CheckFail, "%s %s", desc, strings.Join(duplicated, ", ")))
+			case !known:
+				results = append(results, result(f.Name, check, CheckWarn, "unable to get the %s", desc))
+			default:
+				results = append(results, result(f.Name, check, CheckPass, "%s is unique", desc))
+			}
+		}
+		return results
+	}
+}
+
+func joinInts(list []int) string {
+	s := make([]string, 0, len(list))
+	for _, i := range list {
+		s = append(s, fmt.Sprint(i))
+	}
+	return strings.Join(s, ",")
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
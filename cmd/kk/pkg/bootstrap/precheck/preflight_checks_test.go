@@ -0,0 +1,139 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package precheck
+
+import (
+	"reflect"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+)
+
+func TestParseListening(t *testing.T) {
+	out := `State  Recv-Q Send-Q Local Address:Port Peer Address:Port Process
+LISTEN 0      4096   127.0.0.1:2379      0.0.0.0:*     users:(("etcd",pid=1102,fd=9))
+LISTEN 0      4096   *:6443              *:*           users:(("nginx",pid=812,fd=6))
+LISTEN 0      128    [::]:10250          [::]:*`
+	want := map[int]string{2379: "etcd", 6443: "nginx", 10250: ""}
+	if got := parseListening(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseListening() = %v, want %v", got, want)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	out := `default via 192.168.0.1 dev eth0 proto dhcp metric 100
+blackhole 10.233.64.0/24 proto bird
+10.233.65.0/24 via 192.168.0.12 dev tunl0 proto bird onlink
+192.168.0.0/24 dev eth0 proto kernel scope link src 192.168.0.11
+172.17.0.1 dev docker0 scope link`
+	want := []Route{
+		{Dst: "10.233.65.0/24", Dev: "tunl0"},
+		{Dst: "192.168.0.0/24", Dev: "eth0"},
+		{Dst: "172.17.0.1/32", Dev: "docker0"},
+	}
+	if got := parseRoutes(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRoutes() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckCIDR(t *testing.T) {
+	host := connector.NewHost()
+	host.SetName("node1")
+	host.SetInternalAddress("192.168.0.11")
+	host.SetRole(common.K8s)
+	kubeConf := &common.KubeConf{Cluster: &kubekeyapiv1alpha2.ClusterSpec{
+		Network: kubekeyapiv1alpha2.NetworkConfig{KubePodsCIDR: "10.233.64.0/18", KubeServiceCIDR: "10.233.0.0/18"},
+	}}
+
+	tests := []struct {
+		name   string
+		routes []Route
+		joined bool
+		want   CheckStatus
+	}{
+		{
+			name:   "no overlap",
+			routes: []Route{{Dst: "192.168.0.0/24", Dev: "eth0"}},
+			want:   CheckPass,
+		},
+		{
+			name:   "routes of the network plugin",
+			routes: []Route{{Dst: "10.233.65.0/24", Dev: "tunl0"}, {Dst: "10.233.0.3/32", Dev: "kube-ipvs0"}},
+			want:   CheckPass,
+		},
+		{
+			name:   "overlap the host network",
+			routes: []Route{{Dst: "10.0.0.0/8", Dev: "eth1"}},
+			want:   CheckFail,
+		},
+		{
+			name:   "routes of a bgp network plugin on a node in the cluster",
+			routes: []Route{{Dst: "10.233.65.0/24", Dev: "eth0"}},
+			joined: true,
+			want:   CheckWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkCIDR(host, &NodeFacts{Name: "node1", Routes: tt.routes, Joined: tt.joined}, kubeConf)
+			if len(got) != 1 || got[0].Status != tt.want {
+				t.Errorf("checkCIDR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUnique(t *testing.T) {
+	facts := []*NodeFacts{
+		{Name: "node1", MACs: []string{"52:54:00:00:00:01"}},
+		{Name: "node2", MACs: []string{"52:54:00:00:00:02", "52:54:00:00:00:01"}},
+		{Name: "node3", MACs: []string{"52:54:00:00:00:03"}},
+		{Name: "node4"},
+	}
+	check := checkUnique("MAC", "MAC address", func(f *NodeFacts) []string { return f.MACs })
+	want := []CheckStatus{CheckFail, CheckFail, CheckPass, CheckWarn}
+
+	got := check(facts, nil)
+	for i := range want {
+		if got[i].Status != want[i] {
+			t.Errorf("checkUnique() %s = %s, want %s", got[i].Node, got[i].Status, want[i])
+		}
+	}
+}
+
+func TestCheckHostname(t *testing.T) {
+	facts := []*NodeFacts{{Name: "Node1"}, {Name: "node1"}, {Name: "node2"}}
+	want := []CheckStatus{CheckFail, CheckFail, CheckPass}
+
+	var check clusterCheck
+	for _, c := range clusterChecks {
+		if results := c(facts, nil); len(results) > 0 && results[0].Check == "Hostname" {
+			check = c
+		}
+	}
+	if check == nil {
+		t.Fatal("the Hostname check is not found")
+	}
+	got := check(facts, nil)
+	for i := range want {
+		if got[i].Status != want[i] {
+			t.Errorf("Hostname %s = %s, want %s", got[i].Node, got[i].Status, want[i])
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/certs/templates"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/action"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/task"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
@@ -212,3 +213,251 @@ func (u *UninstallAutoRenewCertsModule) Init() {
 		uninstall,
 	}
 }
+
+// RotateCAGenerateModule generates the new CAs on the local machine.
+type RotateCAGenerateModule struct {
+	common.KubeModule
+}
+
+func (r *RotateCAGenerateModule) Init() {
+	r.Name = "RotateCAGenerateModule"
+	r.Desc = "Generate the new CAs"
+
+	fetch := &task.RemoteTask{
+		Name:     "FetchOldCA",
+		Desc:     "Fetch the CAs in use from control-plane",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(FetchOldCA),
+		Parallel: true,
+	}
+
+	generate := &task.LocalTask{
+		Name:   "GenerateNewCA",
+		Desc:   "Generate the new CAs and the certs signed by them",
+		Action: new(GenerateNewCA),
+	}
+
+	r.Tasks = []task.Interface{
+		fetch,
+		generate,
+	}
+}
+
+// RotateCATrustModule makes all the components trust both the new and the old CAs.
+type RotateCATrustModule struct {
+	common.KubeModule
+}
+
+func (r *RotateCATrustModule) Init() {
+	r.Name = "RotateCATrustModule"
+	r.Desc = "Trust both the new and the old CAs"
+
+	backup := &task.RemoteTask{
+		Name:     "BackupPKI",
+		Desc:     "Backup the certs and kube config files",
+		Hosts:    rotateCAHosts(r.Runtime),
+		Action:   new(BackupPKI),
+		Parallel: true,
+	}
+
+	distribute := &task.RemoteTask{
+		Name:     "DistributeCABundle",
+		Desc:     "Distribute the bundles of the new and the old CAs",
+		Hosts:    rotateCAHosts(r.Runtime),
+		Action:   new(DistributeCA),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	updateKubeConfig := &task.RemoteTask{
+		Name:     "UpdateKubeConfigCA",
+		Desc:     "Update the CA of the kube config files",
+		Hosts:    r.Runtime.GetHostsByRole(common.K8s),
+		Action:   new(UpdateKubeConfigCA),
+		Parallel: true,
+	}
+
+	r.Tasks = append([]task.Interface{
+		backup,
+		distribute,
+		updateKubeConfig,
+	}, rollingRestartTasks(r.Runtime, r.KubeConf)...)
+}
+
+// RotateCALeavesModule re-issues the certificates with the new CAs.
+type RotateCALeavesModule struct {
+	common.KubeModule
+}
+
+func (r *RotateCALeavesModule) Init() {
+	r.Name = "RotateCALeavesModule"
+	r.Desc = "Re-issue the certs with the new CAs"
+
+	sync := &task.RemoteTask{
+		Name:     "SyncLeafCerts",
+		Desc:     "Synchronize the etcd and kubelet client certs",
+		Hosts:    rotateCAHosts(r.Runtime),
+		Action:   new(SyncLeafCerts),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	renew := &task.RemoteTask{
+		Name:     "RenewLeafCerts",
+		Desc:     "Renew control-plane certs",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(RenewLeafCerts),
+		Parallel: false,
+		Retry:    5,
+	}
+
+	// kubeadm writes the first CA of the bundle into the kube config files it renews
+	updateKubeConfig := &task.RemoteTask{
+		Name:     "UpdateKubeConfigCA",
+		Desc:     "Update the CA of the kube config files",
+		Hosts:    r.Runtime.GetHostsByRole(common.K8s),
+		Action:   new(UpdateKubeConfigCA),
+		Parallel: true,
+	}
+
+	r.Tasks = append([]task.Interface{
+		sync,
+		renew,
+		updateKubeConfig,
+	}, rollingRestartTasks(r.Runtime, r.KubeConf)...)
+}
+
+// RotateCADropModule removes the old CAs from the trust bundles.
+type RotateCADropModule struct {
+	common.KubeModule
+}
+
+func (r *RotateCADropModule) Init() {
+	r.Name = "RotateCADropModule"
+	r.Desc = "Drop the old CAs"
+
+	distribute := &task.RemoteTask{
+		Name:     "DistributeNewCA",
+		Desc:     "Distribute the new CAs",
+		Hosts:    rotateCAHosts(r.Runtime),
+		Action:   &DistributeCA{Drop: true},
+		Parallel: true,
+		Retry:    2,
+	}
+
+	updateKubeConfig := &task.RemoteTask{
+		Name:     "UpdateKubeConfigCA",
+		Desc:     "Update the CA of the kube config files",
+		Hosts:    r.Runtime.GetHostsByRole(common.K8s),
+		Action:   new(UpdateKubeConfigCA),
+		Parallel: true,
+	}
+
+	uploadClusterInfo := &task.RemoteTask{
+		Name:     "UploadClusterInfo",
+		Desc:     "Update the CA of the cluster-info",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(UploadClusterInfo),
+		Parallel: true,
+		Retry:    5,
+	}
+
+	copyKubeConfig := &task.RemoteTask{
+		Name:     "CopyKubeConfig",
+		Desc:     "Copy admin.conf to ~/.kube/config",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(kubernetes.CopyKubeConfigForControlPlane),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	fetchKubeConfig := &task.RemoteTask{
+		Name:     "FetchKubeConfig",
+		Desc:     "Fetch kube config file from control-plane",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(FetchKubeConfig),
+		Parallel: true,
+	}
+
+	syncKubeConfig := &task.RemoteTask{
+		Name:  "SyncKubeConfig",
+		Desc:  "Synchronize kube config to worker",
+		Hosts: r.Runtime.GetHostsByRole(common.Worker),
+		Prepare: &prepare.PrepareCollection{
+			new(common.OnlyWorker),
+		},
+		Action:   new(SyneKubeConfigToWorker),
+		Parallel: true,
+		Retry:    3,
+	}
+
+	clean := &task.LocalTask{
+		Name:   "CleanRotateCA",
+		Desc:   "Remove the new CAs from the local machine",
+		Action: new(CleanRotateCA),
+	}
+
+	r.Tasks = append([]task.Interface{
+		distribute,
+		updateKubeConfig,
+	}, rollingRestartTasks(r.Runtime, r.KubeConf)...)
+	r.Tasks = append(r.Tasks,
+		uploadClusterInfo,
+		copyKubeConfig,
+		fetchKubeConfig,
+		syncKubeConfig,
+		clean,
+	)
+}
+
+// rotateCAHosts returns the kubernetes and etcd nodes.
+func rotateCAHosts(runtime connector.ModuleRuntime) []connector.Host {
+	var hosts []connector.Host
+	seen := make(map[string]struct{})
+	for _, host := range append(runtime.GetHostsByRole(common.ETCD), runtime.GetHostsByRole(common.K8s)...) {
+		if _, ok := seen[host.GetName()]; ok {
+			continue
+		}
+		seen[host.GetName()] = struct{}{}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// rollingRestartTasks restarts etcd, the control-plane and the kubelets one node at a time.
+func rollingRestartTasks(runtime connector.ModuleRuntime, kubeConf *common.KubeConf) []task.Interface {
+	var tasks []task.Interface
+	if rotateEtcdCA(kubeConf) {
+		tasks = append(tasks, &task.RemoteTask{
+			Name:     "RestartETCD",
+			Desc:     "Restart etcd one by one",
+			Hosts:    runtime.GetHostsByRole(common.ETCD),
+			Action:   new(RestartEtcd),
+			Parallel: false,
+		})
+	}
+
+	tasks = append(tasks,
+		&task.RemoteTask{
+			Name:     "RestartControlPlane",
+			Desc:     "Restart control-plane one by one",
+			Hosts:    runtime.GetHostsByRole(common.Master),
+			Action:   new(RestartControlPlane),
+			Parallel: false,
+		},
+		&task.RemoteTask{
+			Name:  "RestartKubelet",
+			Desc:  "Restart kubelet one by one",
+			Hosts: runtime.GetHostsByRole(common.Worker),
+			Prepare: &prepare.PrepareCollection{
+				new(common.OnlyWorker),
+			},
+			Action:   new(RestartKubelet),
+			Parallel: false,
+		},
+	)
+	return tasks
+}
@@ -0,0 +1,170 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	OutputTable      = "table"
+	OutputJSON       = "json"
+	OutputYAML       = "yaml"
+	OutputPrometheus = "prometheus"
+)
+
+// CertificatesReport is the expiration of the certificates of the control-plane nodes.
+type CertificatesReport struct {
+	Certificates           []*Certificate   `json:"certificates" yaml:"certificates"`
+	CertificateAuthorities []*CaCertificate `json:"certificateAuthorities" yaml:"certificateAuthorities"`
+}
+
+// Print writes the report in the output format, the table is printed if the format is empty.
+func (r *CertificatesReport) Print(w io.Writer, output string) error {
+	switch output {
+	case "", OutputTable:
+		r.printTable(w)
+	case OutputJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "marshal certificates failed")
+		}
+		_, _ = fmt.Fprintln(w, string(data))
+	case OutputYAML:
+		data, err := yaml.Marshal(r)
+		if err != nil {
+			return errors.Wrap(err, "marshal certificates failed")
+		}
+		_, _ = fmt.Fprint(w, string(data))
+	case OutputPrometheus:
+		r.printMetrics(w)
+	default:
+		return errors.Errorf("invalid output format: %s", output)
+	}
+	return nil
+}
+
+func (r *CertificatesReport) printTable(out io.Writer) {
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CERTIFICATE\tEXPIRES\tRESIDUAL TIME\tCERTIFICATE AUTHORITY\tNODE")
+	for _, cert := range r.Certificates {
+		s := fmt.Sprintf("%s\t%s\t%s\t%s\t%-8v",
+			cert.Name,
+			cert.Expires,
+			cert.Residual,
+			cert.AuthorityName,
+			cert.NodeName,
+		)
+
+		_, _ = fmt.Fprintln(w, s)
+		continue
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "CERTIFICATE AUTHORITY\tEXPIRES\tRESIDUAL TIME\tNODE")
+	for _, caCert := range r.CertificateAuthorities {
+		c := fmt.Sprintf("%s\t%s\t%s\t%-8v",
+			caCert.AuthorityName,
+			caCert.Expires,
+			caCert.Residual,
+			caCert.NodeName,
+		)
+
+		_, _ = fmt.Fprintln(w, c)
+		continue
+	}
+
+	_ = w.Flush()
+}
+
+// printMetrics writes the expiration timestamps in the Prometheus text format,
+// which can be collected by the textfile collector of node_exporter.
+func (r *CertificatesReport) printMetrics(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "# HELP kubekey_certificate_expiration_timestamp_seconds The expiration time of the certificate as a unix timestamp.")
+	_, _ = fmt.Fprintln(w, "# TYPE kubekey_certificate_expiration_timestamp_seconds gauge")
+	for _, cert := range r.Certificates {
+		_, _ = fmt.Fprintf(w, "kubekey_certificate_expiration_timestamp_seconds{node=%q,certificate=%q,authority=%q} %d\n",
+			cert.NodeName, cert.Name, cert.AuthorityName, cert.NotAfter.Unix())
+	}
+	_, _ = fmt.Fprintln(w, "# HELP kubekey_certificate_authority_expiration_timestamp_seconds The expiration time of the certificate authority as a unix timestamp.")
+	_, _ = fmt.Fprintln(w, "# TYPE kubekey_certificate_authority_expiration_timestamp_seconds gauge")
+	for _, ca := range r.CertificateAuthorities {
+		_, _ = fmt.Fprintf(w, "kubekey_certificate_authority_expiration_timestamp_seconds{node=%q,authority=%q} %d\n",
+			ca.NodeName, ca.AuthorityName, ca.NotAfter.Unix())
+	}
+}
+
+// WriteTextFile writes the metrics to the file through a rename, so that the collector never reads a partial file.
+func (r *CertificatesReport) WriteTextFile(path string) error {
+	var buf bytes.Buffer
+	r.printMetrics(&buf)
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.Wrapf(err, "create the temp file of %s failed", path)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "write %s failed", tmp.Name())
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "close %s failed", tmp.Name())
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "chmod %s failed", tmp.Name())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrapf(err, "rename %s to %s failed", tmp.Name(), path)
+	}
+	return nil
+}
+
+// ExpiringWithin returns the certificates and certificate authorities which expire before the deadline.
+func (r *CertificatesReport) ExpiringWithin(deadline time.Time) []string {
+	var expiring []string
+	for _, cert := range r.Certificates {
+		if cert.NotAfter.Before(deadline) {
+			expiring = append(expiring, fmt.Sprintf("%s/%s", cert.NodeName, cert.Name))
+		}
+	}
+	for _, ca := range r.CertificateAuthorities {
+		if ca.NotAfter.Before(deadline) {
+			expiring = append(expiring, fmt.Sprintf("%s/%s", ca.NodeName, ca.AuthorityName))
+		}
+	}
+	return expiring
+}
+
+// ValidOutput reports whether the output format is supported.
+func ValidOutput(output string) bool {
+	switch output {
+	case "", OutputTable, OutputJSON, OutputYAML, OutputPrometheus:
+		return true
+	}
+	return false
+}
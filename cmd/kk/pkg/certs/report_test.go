@@ -0,0 +1,64 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCertificatesReport(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	report := &CertificatesReport{
+		Certificates: []*Certificate{
+			{Name: "apiserver.crt", NotAfter: notAfter, AuthorityName: "ca", NodeName: "node1"},
+		},
+		CertificateAuthorities: []*CaCertificate{
+			{AuthorityName: "ca.crt", NotAfter: notAfter.AddDate(10, 0, 0), NodeName: "node1"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := report.Print(&buf, OutputPrometheus); err != nil {
+		t.Fatal(err)
+	}
+	want := `kubekey_certificate_expiration_timestamp_seconds{node="node1",certificate="apiserver.crt",authority="ca"} 1893553445`
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("Print() = %s, want contains %s", buf.String(), want)
+	}
+	if err := report.Print(&buf, "xml"); err == nil {
+		t.Errorf("Print() with an invalid output format should fail")
+	}
+
+	tests := []struct {
+		name     string
+		deadline time.Time
+		want     int
+	}{
+		{name: "none", deadline: notAfter.AddDate(0, 0, -1), want: 0},
+		{name: "certificate", deadline: notAfter.AddDate(0, 0, 1), want: 1},
+		{name: "all", deadline: notAfter.AddDate(11, 0, 0), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := report.ExpiringWithin(tt.deadline); len(got) != tt.want {
+				t.Errorf("ExpiringWithin() = %v, want %d certificates", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	certutil "k8s.io/client-go/util/cert"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/etcd"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils/certs"
+)
+
+// rotatedCA is a certificate authority replaced by 'kk certs rotate-ca'.
+type rotatedCA struct {
+	// Spec generates the new CA into the local dir, its BaseName is the name of the local files.
+	Spec *certs.KubekeyCert
+	// LocalDir holds the new CA and the leaves signed by it.
+	LocalDir string
+	// RemoteCert and RemoteKey are the paths of the CA on the nodes.
+	RemoteCert string
+	RemoteKey  string
+}
+
+func (r *rotatedCA) newCert() string {
+	return filepath.Join(r.LocalDir, r.Spec.BaseName+".pem")
+}
+
+func (r *rotatedCA) newKey() string {
+	return filepath.Join(r.LocalDir, r.Spec.BaseName+"-key.pem")
+}
+
+func (r *rotatedCA) oldCert() string {
+	return filepath.Join(filepath.Dir(r.LocalDir), "old", filepath.Base(r.RemoteCert))
+}
+
+// bundle trusts both the new and the old CA, the new one goes first so that kubeadm and the signer of
+// kube-controller-manager, which take the first certificate of the file, issue the certificates with the new CA key.
+func (r *rotatedCA) bundle() string {
+	return filepath.Join(filepath.Dir(r.LocalDir), "bundle", filepath.Base(r.RemoteCert))
+}
+
+func rotateCADir(runtime connector.Runtime) string {
+	return filepath.Join(runtime.GetWorkDir(), "pki", "rotate-ca")
+}
+
+func kubernetesCA(runtime connector.Runtime) *rotatedCA {
+	return &rotatedCA{
+		Spec: &certs.KubekeyCert{
+			Name:     "ca",
+			LongName: "self-signed Kubernetes CA to provision identities for other Kubernetes components",
+			BaseName: "ca",
+			Config:   certs.CertConfig{Config: certutil.Config{CommonName: "kubernetes"}},
+		},
+		LocalDir:   filepath.Join(rotateCADir(runtime), "kubernetes"),
+		RemoteCert: filepath.Join(common.KubeCertDir, "ca.crt"),
+		RemoteKey:  filepath.Join(common.KubeCertDir, "ca.key"),
+	}
+}
+
+func frontProxyCA(runtime connector.Runtime) *rotatedCA {
+	return &rotatedCA{
+		Spec: &certs.KubekeyCert{
+			Name:     "front-proxy-ca",
+			LongName: "self-signed CA to provision identities for front proxy",
+			BaseName: "front-proxy-ca",
+			Config:   certs.CertConfig{Config: certutil.Config{CommonName: "front-proxy-ca"}},
+		},
+		LocalDir:   filepath.Join(rotateCADir(runtime), "kubernetes"),
+		RemoteCert: filepath.Join(common.KubeCertDir, "front-proxy-ca.crt"),
+		RemoteKey:  filepath.Join(common.KubeCertDir, "front-proxy-ca.key"),
+	}
+}
+
+func etcdCA(runtime connector.Runtime) *rotatedCA {
+	return &rotatedCA{
+		Spec:       etcd.KubekeyCertEtcdCA(),
+		LocalDir:   filepath.Join(rotateCADir(runtime), "etcd"),
+		RemoteCert: filepath.Join(common.ETCDCertDir, "ca.pem"),
+		RemoteKey:  filepath.Join(common.ETCDCertDir, "ca-key.pem"),
+	}
+}
+
+// rotatedCAs returns the CAs trusted by the host.
+func rotatedCAs(runtime connector.Runtime, kubeConf *common.KubeConf, host connector.Host) []*rotatedCA {
+	var cas []*rotatedCA
+	if host.IsRole(common.K8s) {
+		cas = append(cas, kubernetesCA(runtime))
+	}
+	if host.IsRole(common.Master) {
+		cas = append(cas, frontProxyCA(runtime))
+	}
+	if rotateEtcdCA(kubeConf) && (host.IsRole(common.ETCD) || host.IsRole(common.Master)) {
+		cas = append(cas, etcdCA(runtime))
+	}
+	return cas
+}
+
+// rotateEtcdCA returns true if the etcd CA is managed by kubekey.
+func rotateEtcdCA(kubeConf *common.KubeConf) bool {
+	return kubeConf.Cluster.Etcd.Type == kubekeyapiv1alpha2.KubeKey
+}
+
+// FetchOldCA fetches the CAs in use from the control-plane, the etcd CA is there too as it's trusted by kube-apiserver.
+type FetchOldCA struct {
+	common.KubeAction
+}
+
+func (f *FetchOldCA) Execute(runtime connector.Runtime) error {
+	for _, c := range rotatedCAs(runtime, f.KubeConf, runtime.RemoteHost()) {
+		if err := os.MkdirAll(filepath.Dir(c.oldCert()), 0700); err != nil {
+			return errors.Wrapf(err, "create dir %s failed", filepath.Dir(c.oldCert()))
+		}
+		if err := runtime.GetRunner().Fetch(c.oldCert(), c.RemoteCert); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "fetch %s failed", c.RemoteCert)
+		}
+	}
+	return nil
+}
+
+// GenerateNewCA generates the new CAs, the leaves signed by them which are not issued by kubeadm,
+// and the bundles of the new and the old CAs. The files generated by an interrupted run are reused.
+type GenerateNewCA struct {
+	common.KubeAction
+}
+
+func (g *GenerateNewCA) Execute(runtime connector.Runtime) error {
+	cas := []*rotatedCA{kubernetesCA(runtime), frontProxyCA(runtime)}
+	if rotateEtcdCA(g.KubeConf) {
+		cas = append(cas, etcdCA(runtime))
+	}
+	for _, c := range cas {
+		if err := os.MkdirAll(c.LocalDir, 0700); err != nil {
+			return errors.Wrapf(err, "create dir %s failed", c.LocalDir)
+		}
+		if err := certs.GenerateCA(c.Spec, c.LocalDir, g.KubeConf); err != nil {
+			return errors.Wrapf(err, "generate the new %s failed", c.Spec.Name)
+		}
+		if err := writeBundle(c); err != nil {
+			return err
+		}
+	}
+
+	// the kubelet client certificates are issued by kube-controller-manager through CSRs before,
+	// they are replaced by the ones signed by the new CA
+	ca := cas[0]
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		if err := certs.GenerateCerts(kubeletClientCert(host.GetName()), ca.Spec, ca.LocalDir, g.KubeConf); err != nil {
+			return errors.Wrapf(err, "generate the kubelet client certificate of %s failed", host.GetName())
+		}
+	}
+
+	if rotateEtcdCA(g.KubeConf) {
+		c := cas[len(cas)-1]
+		altName := etcd.GenerateAltName(g.KubeConf, &runtime)
+		for _, host := range runtime.GetAllHosts() {
+			var leaves []*certs.KubekeyCert
+			if host.IsRole(common.ETCD) {
+				leaves = append(leaves, etcd.KubekeyCertEtcdAdmin(host.GetName(), altName), etcd.KubekeyCertEtcdMember(host.GetName(), altName))
+			}
+			if host.IsRole(common.Master) {
+				leaves = append(leaves, etcd.KubekeyCertEtcdClient(host.GetName(), altName))
+			}
+			for _, leaf := range leaves {
+				if err := certs.GenerateCerts(leaf, c.Spec, c.LocalDir, g.KubeConf); err != nil {
+					return errors.Wrapf(err, "generate the etcd certificate %s failed", leaf.BaseName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func kubeletClientCert(nodeName string) *certs.KubekeyCert {
+	return &certs.KubekeyCert{
+		Name:     "kubelet-client",
+		LongName: "client certificate for the kubelet to connect to the API server",
+		BaseName: fmt.Sprintf("kubelet-client-%s", nodeName),
+		CAName:   "ca",
+		Config: certs.CertConfig{
+			Config: certutil.Config{
+				CommonName:   fmt.Sprintf("system:node:%s", strings.ToLower(nodeName)),
+				Organization: []string{"system:nodes"},
+				Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			},
+		},
+	}
+}
+
+// writeBundle writes the new CA followed by the old ones, a bundle of a previous run may be the old CA.
+func writeBundle(c *rotatedCA) error {
+	newCert, err := ioutil.ReadFile(c.newCert())
+	if err != nil {
+		return errors.Wrapf(err, "read %s failed", c.newCert())
+	}
+	newCA, err := certutil.ParseCertsPEM(newCert)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s failed", c.newCert())
+	}
+	oldCert, err := ioutil.ReadFile(c.oldCert())
+	if err != nil {
+		return errors.Wrapf(err, "read %s failed", c.oldCert())
+	}
+	oldCAs, err := certutil.ParseCertsPEM(oldCert)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s failed", c.oldCert())
+	}
+
+	bundle := bytes.NewBuffer(certs.EncodeCertPEM(newCA[0]))
+	for _, old := range oldCAs {
+		if !old.Equal(newCA[0]) {
+			bundle.Write(certs.EncodeCertPEM(old))
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(c.bundle()), 0700); err != nil {
+		return errors.Wrapf(err, "create dir %s failed", filepath.Dir(c.bundle()))
+	}
+	if err := ioutil.WriteFile(c.bundle(), bundle.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "write %s failed", c.bundle())
+	}
+	return nil
+}
+
+// BackupPKI copies the certificates and the kubeconfig files before they are changed, each run has its own backup.
+type BackupPKI struct {
+	common.KubeAction
+}
+
+func (b *BackupPKI) Execute(runtime connector.Runtime) error {
+	backup := fmt.Sprintf("/etc/kubernetes/pki-backup-%s", time.Now().Format("20060102150405"))
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"mkdir -p %s && (cp -a /etc/kubernetes/pki /etc/kubernetes/*.conf %s 2>/dev/null; "+
+			"if [ -d %s ]; then cp -a %s %s/etcd-ssl; fi)",
+		backup, backup, common.ETCDCertDir, common.ETCDCertDir, backup), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "backup the certificates failed")
+	}
+	return nil
+}
+
+// DistributeCA copies the bundles of the new and old CAs to the nodes, and the keys of the new CAs
+// to the nodes which sign certificates. With Drop, only the new CAs are copied.
+type DistributeCA struct {
+	common.KubeAction
+	Drop bool
+}
+
+func (d *DistributeCA) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	for _, c := range rotatedCAs(runtime, d.KubeConf, host) {
+		cert := c.bundle()
+		if d.Drop {
+			cert = c.newCert()
+		}
+		if err := runtime.GetRunner().SudoScp(cert, c.RemoteCert); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "copy the CA %s failed", c.RemoteCert)
+		}
+		if host.IsRole(common.Master) || c.RemoteCert == etcdCA(runtime).RemoteCert {
+			if err := runtime.GetRunner().SudoScp(c.newKey(), c.RemoteKey); err != nil {
+				return errors.Wrapf(errors.WithStack(err), "copy the CA key %s failed", c.RemoteKey)
+			}
+			if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("chmod 600 %s", c.RemoteKey), false); err != nil {
+				return errors.Wrapf(errors.WithStack(err), "chmod %s failed", c.RemoteKey)
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateKubeConfigCA embeds /etc/kubernetes/pki/ca.crt into the kubeconfig files of the node.
+type UpdateKubeConfigCA struct {
+	common.KubeAction
+}
+
+func (u *UpdateKubeConfigCA) Execute(runtime connector.Runtime) error {
+	files := []string{"kubelet.conf"}
+	if runtime.RemoteHost().IsRole(common.Master) {
+		files = append(files, kubeConfigList...)
+	}
+	for _, file := range files {
+		kubeConfig := filepath.Join(common.KubeConfigDir, file)
+		cluster, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+			"/usr/local/bin/kubectl config view --kubeconfig=%s -o jsonpath='{.clusters[0].name}'", kubeConfig), false)
+		if err != nil {
+			return errors.Wrapf(errors.WithStack(err), "get the cluster of %s failed", kubeConfig)
+		}
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+			"/usr/local/bin/kubectl config set-cluster %s --kubeconfig=%s --certificate-authority=%s --embed-certs=true",
+			strings.TrimSpace(cluster), kubeConfig, kubernetesCA(runtime).RemoteCert), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "update the CA of %s failed", kubeConfig)
+		}
+	}
+	return nil
+}
+
+// RenewLeafCerts renews the certificates issued by kubeadm, which are signed by the first CA of the bundles, i.e. the new CA.
+type RenewLeafCerts struct {
+	common.KubeAction
+}
+
+func (r *RenewLeafCerts) Execute(runtime connector.Runtime) error {
+	version, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubeadm version -o short", false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "kubeadm get version failed")
+	}
+	renew := "/usr/local/bin/kubeadm certs renew"
+	if versionutil.MustParseSemantic(version).LessThan(versionutil.MustParseSemantic("v1.20.0")) {
+		renew = "/usr/local/bin/kubeadm alpha certs renew"
+	}
+
+	certificates := []string{"apiserver", "apiserver-kubelet-client", "front-proxy-client"}
+	certificates = append(certificates, kubeConfigList...)
+	for _, c := range certificates {
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s %s", renew, c), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "renew %s failed", c)
+		}
+	}
+	return nil
+}
+
+// SyncLeafCerts copies the etcd certificates and the kubelet client certificate signed by the new CAs to the node.
+type SyncLeafCerts struct {
+	common.KubeAction
+}
+
+func (s *SyncLeafCerts) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+
+	if rotateEtcdCA(s.KubeConf) {
+		var names []string
+		if host.IsRole(common.ETCD) {
+			names = append(names, fmt.Sprintf("admin-%s", host.GetName()), fmt.Sprintf("member-%s", host.GetName()))
+		}
+		if host.IsRole(common.Master) {
+			names = append(names, fmt.Sprintf("node-%s", host.GetName()))
+		}
+		dir := etcdCA(runtime).LocalDir
+		for _, name := range names {
+			for _, file := range []string{name + ".pem", name + "-key.pem"} {
+				if err := runtime.GetRunner().SudoScp(filepath.Join(dir, file), filepath.Join(common.ETCDCertDir, file)); err != nil {
+					return errors.Wrapf(errors.WithStack(err), "copy the etcd certificate %s failed", file)
+				}
+			}
+		}
+	}
+
+	if !host.IsRole(common.K8s) {
+		return nil
+	}
+	dir := kubernetesCA(runtime).LocalDir
+	name := kubeletClientCert(host.GetName()).BaseName
+	cert, err := ioutil.ReadFile(filepath.Join(dir, name+".pem"))
+	if err != nil {
+		return errors.Wrapf(err, "read the kubelet client certificate of %s failed", host.GetName())
+	}
+	key, err := ioutil.ReadFile(filepath.Join(dir, name+"-key.pem"))
+	if err != nil {
+		return errors.Wrapf(err, "read the kubelet client key of %s failed", host.GetName())
+	}
+	// kubelet reads the certificate and the key from the same file
+	pair := filepath.Join(dir, name+"-pair.pem")
+	if err := ioutil.WriteFile(pair, append(cert, key...), 0600); err != nil {
+		return errors.Wrapf(err, "write %s failed", pair)
+	}
+
+	kubeletPair := fmt.Sprintf("/var/lib/kubelet/pki/kubelet-client-rotate-ca-%s.pem", time.Now().Format("2006-01-02-15-04-05"))
+	if err := runtime.GetRunner().SudoScp(pair, kubeletPair); err != nil {
+		return errors.Wrap(errors.WithStack(err), "copy the kubelet client certificate failed")
+	}
+	kubeConfig := filepath.Join(common.KubeConfigDir, "kubelet.conf")
+	user, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl config view --kubeconfig=%s -o jsonpath='{.users[0].name}'", kubeConfig), false)
+	if err != nil {
+		return errors.Wrapf(errors.WithStack(err), "get the user of %s failed", kubeConfig)
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"chmod 600 %s && ln -sf %s /var/lib/kubelet/pki/kubelet-client-current.pem && "+
+			"/usr/local/bin/kubectl config set-credentials %s --kubeconfig=%s "+
+			"--client-certificate=/var/lib/kubelet/pki/kubelet-client-current.pem --client-key=/var/lib/kubelet/pki/kubelet-client-current.pem",
+		kubeletPair, kubeletPair, strings.TrimSpace(user), kubeConfig), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "update the kubelet client certificate failed")
+	}
+	return nil
+}
+
+// componentRestartTimeout is the time to wait for a component to be healthy after it's restarted.
+const componentRestartTimeout = 5 * time.Minute
+
+// RestartEtcd restarts the etcd member and waits for it to be healthy, the members are restarted one by one.
+type RestartEtcd struct {
+	common.KubeAction
+}
+
+func (r *RestartEtcd) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd("systemctl restart etcd", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart etcd failed")
+	}
+	return waitFor(runtime, fmt.Sprintf("export ETCDCTL_API=3; %s/etcdctl --endpoints=https://%s:2379 "+
+		"--cacert=%s/ca.pem --cert=%s/admin-%s.pem --key=%s/admin-%s-key.pem endpoint health",
+		common.BinDir, host.GetInternalAddress(), common.ETCDCertDir, common.ETCDCertDir, host.GetName(), common.ETCDCertDir, host.GetName()))
+}
+
+// RestartControlPlane restarts the control plane components and the kubelet of the node and waits
+// for kube-apiserver to be ready, the nodes are restarted one by one.
+type RestartControlPlane struct {
+	common.KubeAction
+}
+
+func (r *RestartControlPlane) Execute(runtime connector.Runtime) error {
+	for _, component := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler"} {
+		if _, err := runtime.GetRunner().SudoCmd(restartStaticPodCmd(r.KubeConf, component), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "restart %s failed", component)
+		}
+	}
+	if _, err := runtime.GetRunner().SudoCmd("systemctl restart kubelet", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart kubelet failed")
+	}
+	return waitFor(runtime, fmt.Sprintf("/usr/local/bin/kubectl --kubeconfig=/etc/kubernetes/admin.conf --server=https://%s:%d get --raw=/readyz",
+		runtime.RemoteHost().GetInternalAddress(), kubekeyapiv1alpha2.DefaultApiserverPort))
+}
+
+// RestartKubelet restarts the kubelet of the worker and waits for it to be healthy, the nodes are restarted one by one.
+type RestartKubelet struct {
+	common.KubeAction
+}
+
+func (r *RestartKubelet) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd("systemctl restart kubelet", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart kubelet failed")
+	}
+	return waitFor(runtime, "curl -sf http://127.0.0.1:10248/healthz")
+}
+
+func restartStaticPodCmd(kubeConf *common.KubeConf, component string) string {
+	if kubeConf.Cluster.Kubernetes.ContainerManager == common.Docker {
+		return fmt.Sprintf("docker ps -af name=k8s_%s* -q | xargs --no-run-if-empty docker rm -f", component)
+	}
+	return fmt.Sprintf("/usr/local/bin/crictl ps --name %s -q | xargs --no-run-if-empty /usr/local/bin/crictl stop", component)
+}
+
+// waitFor runs the command until it succeeds or componentRestartTimeout is reached.
+func waitFor(runtime connector.Runtime, cmd string) error {
+	deadline := time.Now().Add(componentRestartTimeout)
+	for {
+		_, err := runtime.GetRunner().SudoCmd(cmd, false)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(err, "%s is not healthy after %s", runtime.RemoteHost().GetName(), componentRestartTimeout)
+		}
+		logger.Log.Debugf("waiting for %s to be healthy: %v", runtime.RemoteHost().GetName(), err)
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// CleanRotateCA removes the new CAs and the certificates generated on the local machine once the old CAs are dropped,
+// so that the next rotation generates new CAs instead of reusing them and the CA keys are not left behind.
+type CleanRotateCA struct {
+	common.KubeAction
+}
+
+func (c *CleanRotateCA) Execute(runtime connector.Runtime) error {
+	if err := os.RemoveAll(rotateCADir(runtime)); err != nil {
+		return errors.Wrapf(err, "remove %s failed", rotateCADir(runtime))
+	}
+	return nil
+}
+
+// UploadClusterInfo updates the CA in the cluster-info ConfigMap, which is used by the nodes to join the cluster.
+type UploadClusterInfo struct {
+	common.KubeAction
+}
+
+func (u *UploadClusterInfo) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"/usr/local/bin/kubeadm init phase bootstrap-token --config=/etc/kubernetes/kubeadm-config.yaml", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "update the cluster-info ConfigMap failed")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils/certs"
+)
+
+func TestWriteBundle(t *testing.T) {
+	newCA := newTestCA(t, "new")
+	oldCA := newTestCA(t, "old")
+
+	dir := t.TempDir()
+	c := &rotatedCA{
+		Spec:       &certs.KubekeyCert{BaseName: "ca"},
+		LocalDir:   filepath.Join(dir, "kubernetes"),
+		RemoteCert: "/etc/kubernetes/pki/ca.crt",
+	}
+	writeTestCerts(t, c.newCert(), newCA)
+
+	tests := []struct {
+		name string
+		old  []*x509.Certificate
+	}{
+		{name: "old CA", old: []*x509.Certificate{oldCA}},
+		// the CA in use is the bundle of an interrupted run
+		{name: "bundle of a previous run", old: []*x509.Certificate{newCA, oldCA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestCerts(t, c.oldCert(), tt.old...)
+			if err := writeBundle(c); err != nil {
+				t.Fatal(err)
+			}
+			got, err := certutil.CertsFromFile(c.bundle())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 2 || !got[0].Equal(newCA) || !got[1].Equal(oldCA) {
+				t.Errorf("writeBundle() wrote %d certificates, want the new CA followed by the old CA", len(got))
+			}
+		})
+	}
+}
+
+func newTestCA(t *testing.T, commonName string) *x509.Certificate {
+	cert, _, err := certs.NewCertificateAuthority(&certs.CertConfig{
+		Config:             certutil.Config{CommonName: commonName},
+		PublicKeyAlgorithm: x509.ECDSA,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func writeTestCerts(t *testing.T, path string, certificates ...*x509.Certificate) {
+	var data []byte
+	for _, cert := range certificates {
+		data = append(data, certs.EncodeCertPEM(cert)...)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -19,6 +19,7 @@ package certs
 import (
 	"encoding/base64"
 	"fmt"
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/certs/templates"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
@@ -32,23 +33,24 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/tabwriter"
 	"time"
 )
 
 type Certificate struct {
-	Name          string
-	Expires       string
-	Residual      string
-	AuthorityName string
-	NodeName      string
+	Name          string    `json:"name" yaml:"name"`
+	Expires       string    `json:"-" yaml:"-"`
+	NotAfter      time.Time `json:"notAfter" yaml:"notAfter"`
+	Residual      string    `json:"residual" yaml:"residual"`
+	AuthorityName string    `json:"authorityName" yaml:"authorityName"`
+	NodeName      string    `json:"nodeName" yaml:"nodeName"`
 }
 
 type CaCertificate struct {
-	AuthorityName string
-	Expires       string
-	Residual      string
-	NodeName      string
+	AuthorityName string    `json:"authorityName" yaml:"authorityName"`
+	Expires       string    `json:"-" yaml:"-"`
+	NotAfter      time.Time `json:"notAfter" yaml:"notAfter"`
+	Residual      string    `json:"residual" yaml:"residual"`
+	NodeName      string    `json:"nodeName" yaml:"nodeName"`
 }
 
 var (
@@ -106,6 +108,14 @@ func (l *ListClusterCerts) Execute(runtime connector.Runtime) error {
 			if err != nil {
 				return err
 			}
+			// the kube config files provided in the external CA mode may refer to the certificate files
+			if len(certContext) == 0 && a.ClientCertificate != "" {
+				output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("cat %s", a.ClientCertificate), false)
+				if err != nil {
+					return errors.Wrapf(err, "get the client certificate of %s failed", kubeConfigFileName)
+				}
+				certContext = []byte(output)
+			}
 			if cert, err := getCertInfo(string(certContext), kubeConfigFileName, host.GetName()); err != nil {
 				return err
 			} else {
@@ -127,11 +137,59 @@ func (l *ListClusterCerts) Execute(runtime connector.Runtime) error {
 		}
 	}
 
+	etcdCert, etcdCA := etcdCertificates(l.KubeConf, host.GetName())
+	if etcdCert != "" {
+		certContext, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("cat %s", etcdCert), false)
+		if err != nil {
+			return errors.Wrap(err, "get etcd certs failed")
+		}
+		cert, err := getCertInfo(certContext, etcdCertName(etcdCert), host.GetName())
+		if err != nil {
+			return err
+		}
+		cert.AuthorityName = "etcd-ca"
+		certificates = append(certificates, cert)
+
+		caCertContext, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("cat %s", etcdCA), false)
+		if err != nil {
+			return errors.Wrap(err, "get etcd certs failed")
+		}
+		caCert, err := getCaCertInfo(caCertContext, etcdCertName(etcdCA), host.GetName())
+		if err != nil {
+			return err
+		}
+		caCertificates = append(caCertificates, caCert)
+	}
+
 	host.GetCache().Set(common.Certificate, certificates)
 	host.GetCache().Set(common.CaCertificate, caCertificates)
 	return nil
 }
 
+// etcdCertificates returns the paths of the client certificate used by kube-apiserver to connect to etcd and its CA.
+func etcdCertificates(kubeConf *common.KubeConf, nodeName string) (string, string) {
+	etcd := kubeConf.Cluster.Etcd
+	switch etcd.Type {
+	case kubekeyapiv1alpha2.KubeKey:
+		return filepath.Join(common.ETCDCertDir, fmt.Sprintf("node-%s.pem", nodeName)), filepath.Join(common.ETCDCertDir, "ca.pem")
+	case kubekeyapiv1alpha2.Kubeadm:
+		return filepath.Join(common.KubeCertDir, "apiserver-etcd-client.crt"), filepath.Join(common.KubeCertDir, "etcd", "ca.crt")
+	case kubekeyapiv1alpha2.External:
+		if etcd.External.CertFile != "" && etcd.External.CAFile != "" {
+			return filepath.Join(common.ETCDCertDir, filepath.Base(etcd.External.CertFile)), filepath.Join(common.ETCDCertDir, filepath.Base(etcd.External.CAFile))
+		}
+	}
+	return "", ""
+}
+
+// etcdCertName distinguishes the etcd certificates from the kubernetes ones with the same file name.
+func etcdCertName(path string) string {
+	if filepath.Dir(path) == common.KubeCertDir {
+		return filepath.Base(path)
+	}
+	return "etcd/" + filepath.Base(path)
+}
+
 func getCertInfo(certContext, certFileName, nodeName string) (*Certificate, error) {
 	certs, err1 := certutil.ParseCertsPEM([]byte(certContext))
 	if err1 != nil {
@@ -151,6 +209,7 @@ func getCertInfo(certContext, certFileName, nodeName string) (*Certificate, erro
 	cert := Certificate{
 		Name:          certFileName,
 		Expires:       certs[0].NotAfter.Format("Jan 02, 2006 15:04 MST"),
+		NotAfter:      certs[0].NotAfter,
 		Residual:      ResidualTime(certs[0].NotAfter),
 		AuthorityName: authorityName,
 		NodeName:      nodeName,
@@ -166,6 +225,7 @@ func getCaCertInfo(certContext, certFileName, nodeName string) (*CaCertificate,
 	cert1 := CaCertificate{
 		AuthorityName: certFileName,
 		Expires:       certs[0].NotAfter.Format("Jan 02, 2006 15:04 MST"),
+		NotAfter:      certs[0].NotAfter,
 		Residual:      ResidualTime(certs[0].NotAfter),
 		NodeName:      nodeName,
 	}
@@ -213,35 +273,21 @@ func (d *DisplayForm) Execute(runtime connector.Runtime) error {
 		caCertificates = append(caCertificates, hostCaCertificates...)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
-	_, _ = fmt.Fprintln(w, "CERTIFICATE\tEXPIRES\tRESIDUAL TIME\tCERTIFICATE AUTHORITY\tNODE")
-	for _, cert := range certificates {
-		s := fmt.Sprintf("%s\t%s\t%s\t%s\t%-8v",
-			cert.Name,
-			cert.Expires,
-			cert.Residual,
-			cert.AuthorityName,
-			cert.NodeName,
-		)
-
-		_, _ = fmt.Fprintln(w, s)
-		continue
+	report := &CertificatesReport{Certificates: certificates, CertificateAuthorities: caCertificates}
+	if err := report.Print(os.Stdout, d.KubeConf.Arg.Output); err != nil {
+		return err
 	}
-	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintln(w, "CERTIFICATE AUTHORITY\tEXPIRES\tRESIDUAL TIME\tNODE")
-	for _, caCert := range caCertificates {
-		c := fmt.Sprintf("%s\t%s\t%s\t%-8v",
-			caCert.AuthorityName,
-			caCert.Expires,
-			caCert.Residual,
-			caCert.NodeName,
-		)
-
-		_, _ = fmt.Fprintln(w, c)
-		continue
+	if d.KubeConf.Arg.TextFile != "" {
+		if err := report.WriteTextFile(d.KubeConf.Arg.TextFile); err != nil {
+			return err
+		}
+	}
+	if d.KubeConf.Arg.WarnDays > 0 {
+		if expiring := report.ExpiringWithin(time.Now().AddDate(0, 0, d.KubeConf.Arg.WarnDays)); len(expiring) > 0 {
+			return errors.Errorf("%d certificates expire within %d days: %s",
+				len(expiring), d.KubeConf.Arg.WarnDays, strings.Join(expiring, ", "))
+		}
 	}
-
-	_ = w.Flush()
 	return nil
 }
 
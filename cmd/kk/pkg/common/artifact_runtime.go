@@ -34,7 +34,12 @@ type ArtifactArgument struct {
 	CriSocket       string
 	Debug           bool
 	IgnoreErr       bool
+	ReportFile      string
 	DownloadCommand func(path, url string) string
+	// Base is the artifact which the delta is created against.
+	Base string
+	// SignKey is the ed25519 private key which the index of the artifact is signed with.
+	SignKey string
 }
 
 type ArtifactRuntime struct {
@@ -48,6 +53,7 @@ func NewArtifactRuntime(arg ArtifactArgument) (*ArtifactRuntime, error) {
 	if err != nil {
 		return nil, err
 	}
+	localRuntime.SetReportFile(arg.ReportFile)
 
 	fp, err := filepath.Abs(arg.ManifestFile)
 	if err != nil {
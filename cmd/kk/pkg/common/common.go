@@ -32,6 +32,7 @@ const (
 	ETCD          = "etcd"
 	K8s           = "k8s"
 	Registry      = "registry"
+	Loadbalancer  = "loadbalancer"
 	KubeKey       = "kubekey"
 	Harbor        = "harbor"
 	DockerCompose = "compose"
@@ -51,6 +52,8 @@ const (
 	RegistryCertDir = "/etc/ssl/registry/ssl"
 
 	HaproxyDir = "/etc/kubekey/haproxy"
+	NginxDir   = "/etc/kubekey/nginx"
+	EnvoyDir   = "/etc/kubekey/envoy"
 
 	IPv4Regexp = "[\\d]+\\.[\\d]+\\.[\\d]+\\.[\\d]+"
 	IPv6Regexp = "[a-f0-9]{1,4}(:[a-f0-9]{1,4}){7}|[a-f0-9]{1,4}(:[a-f0-9]{1,4}){0,7}::[a-f0-9]{0,4}(:[a-f0-9]{1,4}){0,7}"
@@ -70,6 +73,8 @@ const (
 	// global cache key
 	// PreCheckModule
 	NodePreCheck           = "nodePreCheck"
+	NodePreflightFacts     = "nodePreflightFacts"
+	PreflightResults       = "preflightResults"
 	K8sVersion             = "k8sVersion"        // current k8s version
 	MaxK8sVersion          = "maxK8sVersion"     // max k8s version of nodes
 	KubeSphereVersion      = "kubeSphereVersion" // current KubeSphere version
@@ -77,6 +82,7 @@ const (
 	ClusterNodeCRIRuntimes = "ClusterNodeCRIRuntimes"
 	DesiredK8sVersion      = "desiredK8sVersion"
 	PlanK8sVersion         = "planK8sVersion"
+	UpgradePath            = "upgradePath" // the versions to upgrade through until the plan version
 	NodeK8sVersion         = "NodeK8sVersion"
 
 	// ETCDModule
@@ -93,5 +99,6 @@ const (
 	CaCertificate = "caCertificate"
 
 	// Artifact pipeline
-	Artifact = "artifact"
+	Artifact      = "artifact"
+	ArtifactDelta = "artifact-delta"
 )
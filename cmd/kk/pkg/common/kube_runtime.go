@@ -17,8 +17,15 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/clients/ssh/hostkey"
 )
 
 type KubeRuntime struct {
@@ -30,29 +37,44 @@ type KubeRuntime struct {
 }
 
 type Argument struct {
-	NodeName            string
-	FilePath            string
-	KubernetesVersion   string
-	KsEnable            bool
-	KsVersion           string
-	Debug               bool
-	IgnoreErr           bool
-	SkipPullImages      bool
-	SKipPushImages      bool
-	SecurityEnhancement bool
-	DeployLocalStorage  *bool
-	DownloadCommand     func(path, url string) string
-	SkipConfirmCheck    bool
-	ContainerManager    string
-	FromCluster         bool
-	KubeConfig          string
-	Artifact            string
-	InstallPackages     bool
-	ImagesDir           string
-	Namespace           string
-	DeleteCRI           bool
-	Role                string
-	Type                string
+	NodeName              string
+	FilePath              string
+	KubernetesVersion     string
+	KsEnable              bool
+	KsVersion             string
+	Debug                 bool
+	IgnoreErr             bool
+	SkipPullImages        bool
+	SKipPushImages        bool
+	SecurityEnhancement   bool
+	DeployLocalStorage    *bool
+	DownloadCommand       func(path, url string) string
+	SkipConfirmCheck      bool
+	ContainerManager      string
+	FromCluster           bool
+	KubeConfig            string
+	Artifact              string
+	ArtifactPublicKey     string
+	SkipArtifactVerify    bool
+	InstallPackages       bool
+	ImagesDir             string
+	Namespace             string
+	DeleteCRI             bool
+	Role                  string
+	Type                  string
+	Resume                bool
+	ReportFile            string
+	DryRun                bool
+	HostKeyPolicy         string
+	KnownHostsFile        string
+	EtcdSnapshot          string
+	MaxUnavailable        int
+	DrainTimeout          time.Duration
+	SkipDrain             bool
+	IgnorePreflightErrors []string
+	Output                string
+	TextFile              string
+	WarnDays              int
 }
 
 func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
@@ -62,7 +84,19 @@ func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
 		return nil, err
 	}
 
-	base := connector.NewBaseRuntime(cluster.Name, connector.NewDialer(), arg.Debug, arg.IgnoreErr)
+	policy, err := hostkey.ParsePolicy(arg.HostKeyPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	sshDialer := connector.NewDialer()
+	var dialer connector.Connector = sshDialer
+	if arg.DryRun {
+		dialer = connector.NewDryRunDialer()
+	}
+	base := connector.NewBaseRuntime(cluster.Name, dialer, arg.Debug, arg.IgnoreErr)
+	base.SetReportFile(arg.ReportFile)
+	sshDialer.SetHostKeyConfig(hostKeyConfig(policy, arg.KnownHostsFile, base.GetWorkDir()))
 
 	clusterSpec := &cluster.Spec
 	defaultCluster, roleGroups := clusterSpec.SetDefaultClusterSpec()
@@ -93,8 +127,46 @@ func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
 	return r, nil
 }
 
+// hostKeyConfig records the host keys into the work dir in tofu mode if the known hosts file is not specified.
+func hostKeyConfig(policy hostkey.Policy, knownHostsFile, workDir string) hostkey.Config {
+	if policy == hostkey.TOFU && knownHostsFile == "" {
+		knownHostsFile = filepath.Join(workDir, hostkey.KnownHostsFileName)
+	}
+	return hostkey.Config{Policy: policy, KnownHostsFile: knownHostsFile}
+}
+
 // Copy is used to create a copy for Runtime.
 func (k *KubeRuntime) Copy() connector.Runtime {
 	runtime := *k
 	return &runtime
 }
+
+// ConfigHash returns the hash of the cluster config and the arguments which affect the result of a pipeline.
+func (k *KubeRuntime) ConfigHash() string {
+	deployLocalStorage := ""
+	if k.Arg.DeployLocalStorage != nil {
+		deployLocalStorage = fmt.Sprintf("%t", *k.Arg.DeployLocalStorage)
+	}
+	inputs := struct {
+		Cluster             *kubekeyapiv1alpha2.ClusterSpec
+		KsEnable            bool
+		KsVersion           string
+		SecurityEnhancement bool
+		DeployLocalStorage  string
+		Artifact            string
+		InstallPackages     bool
+	}{
+		Cluster:             k.Cluster,
+		KsEnable:            k.Arg.KsEnable,
+		KsVersion:           k.Arg.KsVersion,
+		SecurityEnhancement: k.Arg.SecurityEnhancement,
+		DeployLocalStorage:  deployLocalStorage,
+		Artifact:            k.Arg.Artifact,
+		InstallPackages:     k.Arg.InstallPackages,
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
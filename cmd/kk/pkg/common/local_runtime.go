@@ -27,6 +27,7 @@ import (
 
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/clients/ssh/hostkey"
 )
 
 type LocalRuntime struct {
@@ -54,7 +55,9 @@ func NewLocalRuntime(debug, ingoreErr bool) (LocalRuntime, error) {
 	if err != nil {
 		return localRuntime, err
 	}
-	base := connector.NewBaseRuntime(name, connector.NewDialer(), debug, ingoreErr)
+	dialer := connector.NewDialer()
+	base := connector.NewBaseRuntime(name, dialer, debug, ingoreErr)
+	dialer.SetHostKeyConfig(hostKeyConfig(hostkey.TOFU, "", base.GetWorkDir()))
 
 	host := connector.NewHost()
 	host.Name = name
@@ -217,6 +217,12 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 		if strings.Contains(pod.Name, "haproxy-node") {
 			opt.InternalLoadbalancer = "haproxy"
 		}
+		switch pod.Labels["k8s-app"] {
+		case "kube-nginx":
+			opt.InternalLoadbalancer = "nginx"
+		case "kube-envoy":
+			opt.InternalLoadbalancer = "envoy"
+		}
 	}
 
 	kubeProxyConfig, err := clientset.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "kube-proxy", metav1.GetOptions{})
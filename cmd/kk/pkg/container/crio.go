@@ -0,0 +1,170 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/container/templates"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/files"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/registry"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// crioBinaries are the binaries installed from the cri-o release bundle.
+// crictl is not in the list, it is synced by SyncCrictlBinaries as for containerd.
+var crioBinaries = []string{"crio", "crio-status", "pinns", "conmon", "runc"}
+
+type SyncCrio struct {
+	common.KubeAction
+}
+
+func (s *SyncCrio) Execute(runtime connector.Runtime) error {
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+
+	binariesMapObj, ok := s.PipelineCache.Get(common.KubeBinaries + "-" + runtime.RemoteHost().GetArch())
+	if !ok {
+		return errors.New("get KubeBinary by pipeline cache failed")
+	}
+	binariesMap := binariesMapObj.(map[string]*files.KubeBinary)
+
+	crio, ok := binariesMap[common.Crio]
+	if !ok {
+		return errors.New("get KubeBinary key crio by pipeline cache failed")
+	}
+
+	dst := filepath.Join(common.TmpDir, crio.FileName)
+	if err := runtime.GetRunner().Scp(crio.Path(), dst); err != nil {
+		return errors.Wrap(errors.WithStack(err), "sync crio binaries failed")
+	}
+
+	bins := make([]string, 0, len(crioBinaries))
+	for _, b := range crioBinaries {
+		bins = append(bins, filepath.Join(common.TmpDir, "cri-o/bin", b))
+	}
+	cmd := fmt.Sprintf("tar -zxf %s -C %s && mkdir -p /usr/local/bin && install -m 755 -t /usr/local/bin %s",
+		dst, common.TmpDir, strings.Join(bins, " "))
+	if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "install crio binaries failed")
+	}
+	return nil
+}
+
+type EnableCrio struct {
+	common.KubeAction
+}
+
+func (e *EnableCrio) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl daemon-reload && systemctl enable crio && systemctl start crio",
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "enable and start crio failed")
+	}
+	return nil
+}
+
+type DisableCrio struct {
+	common.KubeAction
+}
+
+func (d *DisableCrio) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl disable crio && systemctl stop crio", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "disable and stop crio failed")
+	}
+
+	// remove crio related files
+	files := []string{
+		"/usr/bin/crictl",
+		filepath.Join("/etc/systemd/system", templates.CrioService.Name()),
+		"/etc/crio",
+		filepath.Join("/etc/containers", templates.CrioRegistries.Name()),
+		filepath.Join("/etc/containers", templates.CrioPolicy.Name()),
+		filepath.Join("/etc", templates.CrictlConfig.Name()),
+		"/var/run/crio",
+		"/run/containers/storage",
+	}
+	for _, b := range crioBinaries {
+		files = append(files, filepath.Join("/usr/local/bin", b))
+	}
+	if d.KubeConf.Cluster.Registry.DataRoot != "" {
+		files = append(files, d.KubeConf.Cluster.Registry.DataRoot)
+	} else {
+		files = append(files, "/var/lib/containers/storage")
+	}
+
+	for _, file := range files {
+		_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s", file), true)
+	}
+	return nil
+}
+
+// CrioAuths returns the auths of the registries which have both username and password, sorted by registry.
+func CrioAuths(kubeConf *common.KubeConf) []templates.CrioAuthEntry {
+	auths := registry.DockerRegistryAuthEntries(kubeConf.Cluster.Registry.Auths)
+	entries := make([]templates.CrioAuthEntry, 0, len(auths))
+	for repo, entry := range auths {
+		if len(entry.Username) == 0 || len(entry.Password) == 0 {
+			continue
+		}
+		entries = append(entries, templates.CrioAuthEntry{
+			Registry: repo,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(entry.Username + ":" + entry.Password)),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Registry < entries[j].Registry
+	})
+	return entries
+}
+
+// InsecureRegistriesWithAuths returns the insecure registries and the registries whose auth skips the TLS verification.
+func InsecureRegistriesWithAuths(kubeConf *common.KubeConf) []string {
+	registries := make([]string, 0, len(kubeConf.Cluster.Registry.InsecureRegistries))
+	seen := make(map[string]struct{})
+	for _, repo := range kubeConf.Cluster.Registry.InsecureRegistries {
+		if _, ok := seen[repo]; !ok {
+			seen[repo] = struct{}{}
+			registries = append(registries, repo)
+		}
+	}
+
+	auths := registry.DockerRegistryAuthEntries(kubeConf.Cluster.Registry.Auths)
+	repos := make([]string, 0, len(auths))
+	for repo, entry := range auths {
+		if entry.SkipTLSVerify {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		if _, ok := seen[repo]; !ok {
+			seen[repo] = struct{}{}
+			registries = append(registries, repo)
+		}
+	}
+	return registries
+}
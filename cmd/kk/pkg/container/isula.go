@@ -0,0 +1,112 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/container/templates"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/registry"
+	"github.com/pkg/errors"
+)
+
+// iSulad has no release bundle, it is installed from the package repository of openEuler.
+const isulaPackage = "iSulad"
+
+type InstallIsulaPackage struct {
+	common.KubeAction
+}
+
+func (i *InstallIsulaPackage) Execute(runtime connector.Runtime) error {
+	cmd := fmt.Sprintf("if command -v dnf >/dev/null 2>&1; then dnf install -y %s; else yum install -y %s; fi",
+		isulaPackage, isulaPackage)
+	if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "install isulad failed")
+	}
+	return nil
+}
+
+type EnableIsula struct {
+	common.KubeAction
+}
+
+func (e *EnableIsula) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl daemon-reload && systemctl enable isulad && systemctl restart isulad",
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "enable and start isulad failed")
+	}
+	return nil
+}
+
+type IsulaLoginRegistry struct {
+	common.KubeAction
+}
+
+func (p *IsulaLoginRegistry) Execute(runtime connector.Runtime) error {
+	auths := registry.DockerRegistryAuthEntries(p.KubeConf.Cluster.Registry.Auths)
+
+	for repo, entry := range auths {
+		if len(entry.Username) == 0 || len(entry.Password) == 0 {
+			continue
+		}
+		cmd := fmt.Sprintf("isula login --username '%s' --password '%s' %s", entry.Username, entry.Password, repo)
+		if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+			return errors.Wrapf(err, "login registry %s failed", repo)
+		}
+	}
+	return nil
+}
+
+type DisableIsula struct {
+	common.KubeAction
+}
+
+func (d *DisableIsula) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl disable isulad && systemctl stop isulad", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "disable and stop isulad failed")
+	}
+
+	cmd := fmt.Sprintf("if command -v dnf >/dev/null 2>&1; then dnf remove -y %s; else yum remove -y %s; fi",
+		isulaPackage, isulaPackage)
+	if _, err := runtime.GetRunner().SudoCmd(cmd, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "remove isulad failed")
+	}
+
+	// remove isulad related files
+	files := []string{
+		"/usr/bin/crictl",
+		filepath.Join("/etc/systemd/system", templates.IsulaService.Name()),
+		filepath.Join("/etc/isulad", templates.IsulaConfig.Name()),
+		filepath.Join("/etc", templates.CrictlConfig.Name()),
+		"/var/run/isulad",
+	}
+	if d.KubeConf.Cluster.Registry.DataRoot != "" {
+		files = append(files, d.KubeConf.Cluster.Registry.DataRoot)
+	} else {
+		files = append(files, "/var/lib/isulad")
+	}
+
+	for _, file := range files {
+		_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s", file), true)
+	}
+	return nil
+}
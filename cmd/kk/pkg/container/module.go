@@ -52,9 +52,9 @@ func (i *InstallContainerModule) Init() {
 	case common.Conatinerd:
 		i.Tasks = InstallContainerd(i)
 	case common.Crio:
-		// TODO: Add the steps of cri-o's installation.
+		i.Tasks = InstallCrio(i)
 	case common.Isula:
-		// TODO: Add the steps of iSula's installation.
+		i.Tasks = InstallIsula(i)
 	default:
 		logger.Log.Fatalf("Unsupported container runtime: %s", strings.TrimSpace(i.KubeConf.Cluster.Kubernetes.ContainerManager))
 	}
@@ -247,6 +247,282 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 	}
 }
 
+func InstallCrio(m *InstallContainerModule) []task.Interface {
+	syncCrio := &task.RemoteTask{
+		Name:  "SyncCrio",
+		Desc:  "Sync crio binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action:   new(SyncCrio),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	syncCrictlBinaries := &task.RemoteTask{
+		Name:  "SyncCrictlBinaries",
+		Desc:  "Sync crictl binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrictlExist{Not: true},
+		},
+		Action:   new(SyncCrictlBinaries),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	generateCrioService := &task.RemoteTask{
+		Name:  "GenerateCrioService",
+		Desc:  "Generate crio service",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioService,
+			Dst:      filepath.Join("/etc/systemd/system", templates.CrioService.Name()),
+		},
+		Parallel: true,
+	}
+
+	auths := CrioAuths(m.KubeConf)
+	generateCrioConfig := &task.RemoteTask{
+		Name:  "GenerateCrioConfig",
+		Desc:  "Generate crio config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioConfig,
+			Dst:      filepath.Join("/etc/crio/", templates.CrioConfig.Name()),
+			Data: util.Data{
+				"SandBoxImage": images.GetImage(m.Runtime, m.KubeConf, "pause").ImageName(),
+				"Auths":        len(auths) != 0,
+				"DataRoot":     templates.DataRoot(m.KubeConf),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrioPolicy := &task.RemoteTask{
+		Name:  "GenerateCrioPolicy",
+		Desc:  "Generate crio signature policy",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioPolicy,
+			Dst:      filepath.Join("/etc/containers/", templates.CrioPolicy.Name()),
+		},
+		Parallel: true,
+	}
+
+	generateCrioRegistries := &task.RemoteTask{
+		Name:  "GenerateCrioRegistries",
+		Desc:  "Generate crio registries config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioRegistries,
+			Dst:      filepath.Join("/etc/containers/", templates.CrioRegistries.Name()),
+			Data: util.Data{
+				"Mirrors":            templates.CrioMirrors(m.KubeConf),
+				"InsecureRegistries": InsecureRegistriesWithAuths(m.KubeConf),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrioAuth := &task.RemoteTask{
+		Name:  "GenerateCrioAuth",
+		Desc:  "Add auths to crio",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+			&PrivateRegistryAuth{},
+		},
+		Action: &action.Template{
+			Template: templates.CrioAuth,
+			Dst:      filepath.Join("/etc/crio/", templates.CrioAuth.Name()),
+			Data: util.Data{
+				"Auths": auths,
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrictlConfig := &task.RemoteTask{
+		Name:  "GenerateCrictlConfig",
+		Desc:  "Generate crictl config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrictlConfig,
+			Dst:      filepath.Join("/etc/", templates.CrictlConfig.Name()),
+			Data: util.Data{
+				"Endpoint": m.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint,
+			},
+		},
+		Parallel: true,
+	}
+
+	enableCrio := &task.RemoteTask{
+		Name:  "EnableCrio",
+		Desc:  "Enable crio",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action:   new(EnableCrio),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		syncCrio,
+		syncCrictlBinaries,
+		generateCrioService,
+		generateCrioConfig,
+		generateCrioPolicy,
+		generateCrioRegistries,
+		generateCrioAuth,
+		generateCrictlConfig,
+		enableCrio,
+	}
+}
+
+func InstallIsula(m *InstallContainerModule) []task.Interface {
+	installIsula := &task.RemoteTask{
+		Name:  "InstallIsula",
+		Desc:  "Install isulad",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action:   new(InstallIsulaPackage),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	syncCrictlBinaries := &task.RemoteTask{
+		Name:  "SyncCrictlBinaries",
+		Desc:  "Sync crictl binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrictlExist{Not: true},
+		},
+		Action:   new(SyncCrictlBinaries),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	generateIsulaService := &task.RemoteTask{
+		Name:  "GenerateIsulaService",
+		Desc:  "Generate isulad service",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.IsulaService,
+			Dst:      filepath.Join("/etc/systemd/system", templates.IsulaService.Name()),
+		},
+		Parallel: true,
+	}
+
+	generateIsulaConfig := &task.RemoteTask{
+		Name:  "GenerateIsulaConfig",
+		Desc:  "Generate isulad config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.IsulaConfig,
+			Dst:      filepath.Join("/etc/isulad/", templates.IsulaConfig.Name()),
+			Data: util.Data{
+				"Mirrors":            templates.IsulaMirrors(m.KubeConf),
+				"InsecureRegistries": templates.IsulaInsecureRegistries(m.KubeConf, InsecureRegistriesWithAuths(m.KubeConf)),
+				"SandBoxImage":       images.GetImage(m.Runtime, m.KubeConf, "pause").ImageName(),
+				"DataRoot":           templates.DataRoot(m.KubeConf),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrictlConfig := &task.RemoteTask{
+		Name:  "GenerateCrictlConfig",
+		Desc:  "Generate crictl config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrictlConfig,
+			Dst:      filepath.Join("/etc/", templates.CrictlConfig.Name()),
+			Data: util.Data{
+				"Endpoint": m.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint,
+			},
+		},
+		Parallel: true,
+	}
+
+	enableIsula := &task.RemoteTask{
+		Name:  "EnableIsula",
+		Desc:  "Enable isulad",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action:   new(EnableIsula),
+		Parallel: true,
+	}
+
+	isulaLoginRegistry := &task.RemoteTask{
+		Name:  "Login PrivateRegistry",
+		Desc:  "Add auths to container runtime",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{},
+			&PrivateRegistryAuth{},
+		},
+		Action:   new(IsulaLoginRegistry),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		installIsula,
+		syncCrictlBinaries,
+		generateIsulaService,
+		generateIsulaConfig,
+		generateCrictlConfig,
+		enableIsula,
+		isulaLoginRegistry,
+	}
+}
+
 type UninstallContainerModule struct {
 	common.KubeModule
 	Skip bool
@@ -266,9 +542,9 @@ func (i *UninstallContainerModule) Init() {
 	case common.Conatinerd:
 		i.Tasks = UninstallContainerd(i)
 	case common.Crio:
-		// TODO: Add the steps of cri-o's installation.
+		i.Tasks = UninstallCrio(i)
 	case common.Isula:
-		// TODO: Add the steps of iSula's installation.
+		i.Tasks = UninstallIsula(i)
 	default:
 		logger.Log.Fatalf("Unsupported container runtime: %s", strings.TrimSpace(i.KubeConf.Cluster.Kubernetes.ContainerManager))
 	}
@@ -309,6 +585,40 @@ func UninstallContainerd(m *UninstallContainerModule) []task.Interface {
 	}
 }
 
+func UninstallCrio(m *UninstallContainerModule) []task.Interface {
+	disableCrio := &task.RemoteTask{
+		Name:  "UninstallCrio",
+		Desc:  "Uninstall crio",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&CrioExist{Not: false},
+		},
+		Action:   new(DisableCrio),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		disableCrio,
+	}
+}
+
+func UninstallIsula(m *UninstallContainerModule) []task.Interface {
+	disableIsula := &task.RemoteTask{
+		Name:  "UninstallIsula",
+		Desc:  "Uninstall isulad",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&IsulaInstalled{},
+		},
+		Action:   new(DisableIsula),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		disableIsula,
+	}
+}
+
 type CriMigrateModule struct {
 	common.KubeModule
 
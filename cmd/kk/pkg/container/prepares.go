@@ -17,6 +17,7 @@
 package container
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
@@ -80,6 +81,65 @@ func (c *ContainerdExist) PreCheck(runtime connector.Runtime) (bool, error) {
 	return !c.Not, nil
 }
 
+type CrioExist struct {
+	common.KubePrepare
+	Not bool
+}
+
+func (c *CrioExist) PreCheck(runtime connector.Runtime) (bool, error) {
+	output, err := runtime.GetRunner().SudoCmd(
+		"if [ ! -x /usr/local/bin/crio ] || [ ! -e /var/run/crio/crio.sock ]; "+
+			"then echo 'not exist'; "+
+			"fi", false)
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(output, "not exist") {
+		return c.Not, nil
+	}
+	return !c.Not, nil
+}
+
+type IsulaExist struct {
+	common.KubePrepare
+	Not bool
+}
+
+func (i *IsulaExist) PreCheck(runtime connector.Runtime) (bool, error) {
+	output, err := runtime.GetRunner().SudoCmd(
+		"if [ -z $(which isulad) ] || [ ! -e /var/run/isulad.sock ]; "+
+			"then echo 'not exist'; "+
+			"fi", false)
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(output, "not exist") {
+		return i.Not, nil
+	}
+	return !i.Not, nil
+}
+
+// IsulaInstalled checks the package or the binary of isulad rather than its socket,
+// so that a stopped isulad is still uninstalled.
+type IsulaInstalled struct {
+	common.KubePrepare
+	Not bool
+}
+
+func (i *IsulaInstalled) PreCheck(runtime connector.Runtime) (bool, error) {
+	output, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("if rpm -q %s >/dev/null 2>&1 || [ -n \"$(which isulad 2>/dev/null)\" ]; "+
+			"then echo 'installed'; "+
+			"fi", isulaPackage), false)
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(output, "installed") {
+		return !i.Not, nil
+	}
+	return i.Not, nil
+}
+
 type PrivateRegistryAuth struct {
 	common.KubePrepare
 }
@@ -0,0 +1,82 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+)
+
+var CrioConfig = template.Must(template.New("crio.conf").Parse(
+	dedent.Dedent(`[crio]
+{{- if .DataRoot }}
+root = {{ .DataRoot }}
+{{- else }}
+root = "/var/lib/containers/storage"
+{{- end }}
+runroot = "/run/containers/storage"
+storage_driver = "overlay"
+log_dir = "/var/log/crio/pods"
+version_file = "/var/run/crio/version"
+
+[crio.api]
+listen = "/var/run/crio/crio.sock"
+stream_address = "127.0.0.1"
+stream_port = "0"
+grpc_max_send_msg_size = 16777216
+grpc_max_recv_msg_size = 16777216
+
+[crio.runtime]
+default_runtime = "runc"
+conmon = "/usr/local/bin/conmon"
+conmon_cgroup = "system.slice"
+cgroup_manager = "systemd"
+pinns_path = "/usr/local/bin/pinns"
+selinux = false
+log_level = "info"
+
+[crio.runtime.runtimes.runc]
+runtime_path = "/usr/local/bin/runc"
+runtime_type = "oci"
+runtime_root = "/run/runc"
+
+[crio.image]
+pause_image = "{{ .SandBoxImage }}"
+{{- if .Auths }}
+global_auth_file = "/etc/crio/auth.json"
+{{- end }}
+signature_policy = "/etc/containers/policy.json"
+
+[crio.network]
+network_dir = "/etc/cni/net.d/"
+plugin_dirs = ["/opt/cni/bin/"]
+
+[crio.metrics]
+enable_metrics = false
+    `)))
+
+// CrioPolicy accepts all images, which matches the behaviour of docker and containerd.
+var CrioPolicy = template.Must(template.New("policy.json").Parse(
+	dedent.Dedent(`{
+  "default": [
+    {
+      "type": "insecureAcceptAnything"
+    }
+  ]
+}
+    `)))
@@ -0,0 +1,88 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/lithammer/dedent"
+)
+
+// CrioRegistries is the containers-registries.conf(5) used by cri-o.
+var CrioRegistries = template.Must(template.New("registries.conf").Parse(
+	dedent.Dedent(`unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+prefix = "docker.io"
+location = "registry-1.docker.io"
+{{- range .Mirrors }}
+
+[[registry.mirror]]
+location = "{{ .Location }}"
+{{- if .Insecure }}
+insecure = true
+{{- end }}
+{{- end }}
+{{- range .InsecureRegistries }}
+
+[[registry]]
+prefix = "{{ . }}"
+location = "{{ . }}"
+insecure = true
+{{- end }}
+    `)))
+
+// CrioAuth is the global auth file of cri-o, it has the same format as the docker config.json.
+var CrioAuth = template.Must(template.New("auth.json").Parse(
+	dedent.Dedent(`{
+  "auths": {
+{{- range $i, $entry := .Auths }}
+    {{- if $i }},{{ end }}
+    "{{ $entry.Registry }}": {
+      "auth": "{{ $entry.Auth }}"
+    }
+{{- end }}
+  }
+}
+    `)))
+
+type CrioAuthEntry struct {
+	Registry string
+	// Auth is the base64 encoded "username:password".
+	Auth string
+}
+
+type CrioMirror struct {
+	Location string
+	Insecure bool
+}
+
+// CrioMirrors converts the registry mirrors to the locations of registries.conf, which have no scheme.
+func CrioMirrors(kubeConf *common.KubeConf) []CrioMirror {
+	var mirrors []CrioMirror
+	for _, mirror := range kubeConf.Cluster.Registry.RegistryMirrors {
+		m := CrioMirror{Location: strings.TrimSuffix(mirror, "/")}
+		if strings.HasPrefix(m.Location, "http://") {
+			m.Insecure = true
+		}
+		m.Location = strings.TrimPrefix(strings.TrimPrefix(m.Location, "http://"), "https://")
+		mirrors = append(mirrors, m)
+	}
+	return mirrors
+}
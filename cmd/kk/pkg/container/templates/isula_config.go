@@ -0,0 +1,92 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/lithammer/dedent"
+)
+
+var IsulaConfig = template.Must(template.New("daemon.json").Parse(
+	dedent.Dedent(`{
+  "group": "isula",
+  "default-runtime": "lcr",
+  {{- if .DataRoot }}
+  "graph": {{ .DataRoot }},
+  {{- else }}
+  "graph": "/var/lib/isulad",
+  {{- end }}
+  "state": "/var/run/isulad",
+  "engine": "lcr",
+  "log-level": "ERROR",
+  "pidfile": "/var/run/isulad.pid",
+  "log-opts": {
+    "log-file-mode": "0600",
+    "log-path": "/var/lib/isulad",
+    "max-file": "1",
+    "max-size": "30KB"
+  },
+  "log-driver": "stdout",
+  "container-log": {
+    "driver": "json-file"
+  },
+  "hook-spec": "/etc/default/isulad/hooks/default.json",
+  "start-timeout": "2m",
+  "storage-driver": "overlay2",
+  "storage-opts": [
+    "overlay2.override_kernel_check=true"
+  ],
+  "registry-mirrors": [{{ .Mirrors }}],
+  "insecure-registries": [{{ .InsecureRegistries }}],
+  "pod-sandbox-image": "{{ .SandBoxImage }}",
+  "native.umask": "secure",
+  "network-plugin": "cni",
+  "cni-bin-dir": "/opt/cni/bin",
+  "cni-conf-dir": "/etc/cni/net.d",
+  "image-layer-check": false,
+  "use-decrypted-key": true,
+  "insecure-skip-verify-enforce": false
+}
+    `)))
+
+// IsulaMirrors returns the registry mirrors of isulad, which are hosts without scheme.
+func IsulaMirrors(kubeConf *common.KubeConf) string {
+	var mirrorsArr []string
+	for _, mirror := range CrioMirrors(kubeConf) {
+		mirrorsArr = append(mirrorsArr, fmt.Sprintf("\"%s\"", mirror.Location))
+	}
+	mirrorsArr = append(mirrorsArr, "\"docker.io\"")
+	return strings.Join(mirrorsArr, ", ")
+}
+
+// IsulaInsecureRegistries returns the insecure registries of isulad. The mirrors served over http are insecure too.
+func IsulaInsecureRegistries(kubeConf *common.KubeConf, registries []string) string {
+	var registriesArr []string
+	for _, mirror := range CrioMirrors(kubeConf) {
+		if mirror.Insecure {
+			registriesArr = append(registriesArr, fmt.Sprintf("\"%s\"", mirror.Location))
+		}
+	}
+	for _, repo := range registries {
+		registriesArr = append(registriesArr, fmt.Sprintf("\"%s\"", repo))
+	}
+	return strings.Join(registriesArr, ", ")
+}
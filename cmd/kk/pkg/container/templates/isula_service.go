@@ -0,0 +1,49 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+)
+
+var IsulaService = template.Must(template.New("isulad.service").Parse(
+	dedent.Dedent(`[Unit]
+Description=iSulad Application Container Engine
+After=network.target
+
+[Service]
+Type=notify
+EnvironmentFile=-/etc/sysconfig/iSulad
+ExecStart=/usr/bin/isulad $OPTIONS
+ExecReload=/bin/kill -s HUP $MAINPID
+LimitNOFILE=1048576
+LimitNPROC=infinity
+LimitCORE=infinity
+TimeoutStartSec=0
+Delegate=yes
+KillMode=process
+Restart=on-failure
+StartLimitBurst=3
+StartLimitInterval=60s
+TimeoutStopSec=10
+OOMScoreAdjust=-500
+
+[Install]
+WantedBy=multi-user.target
+    `)))
@@ -18,6 +18,7 @@ package connector
 
 import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/pkg/clients/ssh/hostkey"
 	"sync"
 	"time"
 )
@@ -25,6 +26,7 @@ import (
 type Dialer struct {
 	lock        sync.Mutex
 	connections map[string]Connection
+	hostKey     hostkey.Config
 }
 
 func NewDialer() *Dialer {
@@ -34,13 +36,15 @@ func NewDialer() *Dialer {
 }
 
 func (d *Dialer) Connect(host Host) (Connection, error) {
-	var err error
-
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
 	conn, ok := d.connections[host.GetName()]
 	if !ok {
+		hostKeyCallback, err := d.hostKey.Callback()
+		if err != nil {
+			return nil, err
+		}
 		opts := Cfg{
 			Username:   host.GetUser(),
 			Port:       host.GetPort(),
@@ -49,6 +53,9 @@ func (d *Dialer) Connect(host Host) (Connection, error) {
 			PrivateKey: host.GetPrivateKey(),
 			KeyFile:    host.GetPrivateKeyPath(),
 			Timeout:    time.Duration(host.GetTimeout()) * time.Second,
+			Bastions:   host.GetBastions(),
+
+			HostKeyCallback: hostKeyCallback,
 		}
 		conn, err = NewConnection(opts)
 		if err != nil {
@@ -60,6 +67,13 @@ func (d *Dialer) Connect(host Host) (Connection, error) {
 	return conn, nil
 }
 
+// SetHostKeyConfig sets the policy to verify the host keys of the new connections.
+func (d *Dialer) SetHostKeyConfig(c hostkey.Config) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hostKey = c
+}
+
 func (d *Dialer) Close(host Host) {
 	conn, ok := d.connections[host.GetName()]
 	if !ok {
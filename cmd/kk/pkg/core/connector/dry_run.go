@@ -0,0 +1,179 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	RecordExec  = "exec"
+	RecordScp   = "scp"
+	RecordFetch = "fetch"
+	RecordMkdir = "mkdir"
+
+	// maxRecordedContent is the max size of a copied file whose content is kept in the record.
+	maxRecordedContent = 256 * 1024
+)
+
+// dryRunProbes are the outputs of the commands whose results are parsed to render the plan, e.g. the cgroup driver
+// of the container runtime, which is not installed in dry-run mode. They answer with the systemd driver configured by kubekey.
+var dryRunProbes = map[string]string{
+	"docker info | grep 'Cgroup Driver'":          "Cgroup Driver: systemd",
+	"isula info | grep 'Cgroup Driver'":           "Cgroup Driver: systemd",
+	"crio config | grep cgroup_manager":           "cgroup_manager = \"systemd\"",
+	"containerd config dump | grep SystemdCgroup": "SystemdCgroup = true",
+}
+
+// Record is an operation that a dry-run connection would have executed on the remote host.
+type Record struct {
+	Type    string
+	Command string
+	Local   string
+	Remote  string
+	// Content is the content of the copied file if it's a text file, such as a rendered template.
+	Content string
+}
+
+// DryRunDialer is a Connector whose connections record the operations instead of executing them.
+type DryRunDialer struct {
+	lock    sync.Mutex
+	hosts   []string
+	records map[string][]Record
+}
+
+func NewDryRunDialer() *DryRunDialer {
+	return &DryRunDialer{
+		hosts:   make([]string, 0),
+		records: make(map[string][]Record),
+	}
+}
+
+// IsDryRun reports whether the connector only records the operations.
+func IsDryRun(c Connector) bool {
+	_, ok := c.(*DryRunDialer)
+	return ok
+}
+
+func (d *DryRunDialer) Connect(host Host) (Connection, error) {
+	return &dryRunConnection{dialer: d}, nil
+}
+
+func (d *DryRunDialer) Close(host Host) {
+}
+
+// Hosts returns the names of the hosts in the order they were first operated on.
+func (d *DryRunDialer) Hosts() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]string{}, d.hosts...)
+}
+
+// Records returns the operations recorded for the host.
+func (d *DryRunDialer) Records(hostName string) []Record {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]Record{}, d.records[hostName]...)
+}
+
+func (d *DryRunDialer) record(host Host, r Record) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	name := host.GetName()
+	if _, ok := d.records[name]; !ok {
+		d.hosts = append(d.hosts, name)
+	}
+	d.records[name] = append(d.records[name], r)
+}
+
+type dryRunConnection struct {
+	dialer *DryRunDialer
+}
+
+func (c *dryRunConnection) Exec(cmd string, host Host) (stdout string, code int, err error) {
+	c.dialer.record(host, Record{Type: RecordExec, Command: cmd})
+	return probeOutput(cmd), 0, nil
+}
+
+func (c *dryRunConnection) PExec(cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer, host Host) (code int, err error) {
+	c.dialer.record(host, Record{Type: RecordExec, Command: cmd})
+	return 0, nil
+}
+
+func (c *dryRunConnection) Fetch(local, remote string, host Host) error {
+	c.dialer.record(host, Record{Type: RecordFetch, Local: local, Remote: remote})
+	return nil
+}
+
+func (c *dryRunConnection) Scp(local, remote string, host Host) error {
+	c.dialer.record(host, Record{Type: RecordScp, Local: local, Remote: remote, Content: textContent(local)})
+	return nil
+}
+
+func (c *dryRunConnection) RemoteFileExist(remote string, host Host) bool {
+	c.dialer.record(host, Record{Type: RecordExec, Command: fmt.Sprintf("test -e %s", remote)})
+	return false
+}
+
+func (c *dryRunConnection) RemoteDirExist(remote string, host Host) (bool, error) {
+	c.dialer.record(host, Record{Type: RecordExec, Command: fmt.Sprintf("test -d %s", remote)})
+	return false, nil
+}
+
+func (c *dryRunConnection) MkDirAll(path string, mode string, host Host) error {
+	c.dialer.record(host, Record{Type: RecordMkdir, Remote: path})
+	return nil
+}
+
+func (c *dryRunConnection) Chmod(path string, mode os.FileMode) error {
+	return nil
+}
+
+func (c *dryRunConnection) Close() {
+}
+
+// probeOutput returns the output of the probe run by the command, or empty if it's not a probe.
+func probeOutput(cmd string) string {
+	for probe, output := range dryRunProbes {
+		if strings.Contains(cmd, probe) {
+			return output
+		}
+	}
+	return ""
+}
+
+// textContent returns the content of the local file if it's a small text file.
+func textContent(local string) string {
+	fi, err := os.Stat(local)
+	if err != nil || fi.IsDir() || fi.Size() > maxRecordedContent {
+		return ""
+	}
+	data, err := ioutil.ReadFile(local)
+	if err != nil {
+		return ""
+	}
+	if !strings.HasPrefix(http.DetectContentType(data), "text/") {
+		return ""
+	}
+	return string(data)
+}
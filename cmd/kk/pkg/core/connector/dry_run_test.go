@@ -0,0 +1,52 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"testing"
+)
+
+func TestDryRunProbe(t *testing.T) {
+	host := NewHost()
+	host.SetName("node1")
+	dialer := NewDryRunDialer()
+	conn, err := dialer.Connect(host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{cmd: SudoPrefix("containerd config dump | grep SystemdCgroup"), want: "SystemdCgroup = true"},
+		{cmd: SudoPrefix("docker info | grep 'Cgroup Driver'"), want: "Cgroup Driver: systemd"},
+		{cmd: SudoPrefix("systemctl restart kubelet"), want: ""},
+	}
+	for _, tt := range tests {
+		got, _, err := conn.Exec(tt.cmd, host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Exec(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+	if records := dialer.Records("node1"); len(records) != len(tests) {
+		t.Errorf("recorded %d commands, want %d", len(records), len(tests))
+	}
+}
@@ -31,12 +31,25 @@ type BaseHost struct {
 	PrivateKeyPath  string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
 	Arch            string `yaml:"arch,omitempty" json:"arch,omitempty"`
 	Timeout         int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Bastions is the chain of jump hosts used to reach the host, ordered from the first hop to the last hop.
+	Bastions []Bastion `yaml:"bastions,omitempty" json:"bastions,omitempty"`
 
 	Roles     []string        `json:"-"`
 	RoleTable map[string]bool `json:"-"`
 	Cache     *cache.Cache    `json:"-"`
 }
 
+// Bastion defines a jump host and the credentials used to log in to it.
+type Bastion struct {
+	Address        string `yaml:"address,omitempty" json:"address,omitempty"`
+	Port           int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User           string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password       string `yaml:"password,omitempty" json:"password,omitempty"`
+	PrivateKey     string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
+	PrivateKeyPath string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
+	Timeout        int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
 func NewHost() *BaseHost {
 	return &BaseHost{
 		Roles:     make([]string, 0, 0),
@@ -109,6 +122,14 @@ func (b *BaseHost) SetPrivateKeyPath(path string) {
 	b.PrivateKeyPath = path
 }
 
+func (b *BaseHost) GetBastions() []Bastion {
+	return b.Bastions
+}
+
+func (b *BaseHost) SetBastions(bastions []Bastion) {
+	b.Bastions = bastions
+}
+
 func (b *BaseHost) GetArch() string {
 	return b.Arch
 }
@@ -60,6 +60,8 @@ type Runtime interface {
 	GetConnector() Connector
 	SetConnector(c Connector)
 	RemoteHost() Host
+	GetReportFile() string
+	SetReportFile(path string)
 	Copy() Runtime
 	ModuleRuntime
 }
@@ -81,6 +83,8 @@ type Host interface {
 	SetPrivateKey(privateKey string)
 	GetPrivateKeyPath() string
 	SetPrivateKeyPath(path string)
+	GetBastions() []Bastion
+	SetBastions(bastions []Bastion)
 	GetArch() string
 	SetArch(arch string)
 	GetTimeout() int64
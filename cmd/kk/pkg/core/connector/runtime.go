@@ -33,6 +33,7 @@ type BaseRuntime struct {
 	connector       Connector
 	runner          *Runner
 	workDir         string
+	reportFile      string
 	verbose         bool
 	ignoreErr       bool
 	allHosts        []Host
@@ -119,6 +120,14 @@ func (b *BaseRuntime) GetWorkDir() string {
 	return b.workDir
 }
 
+func (b *BaseRuntime) GetReportFile() string {
+	return b.reportFile
+}
+
+func (b *BaseRuntime) SetReportFile(path string) {
+	b.reportFile = path
+}
+
 func (b *BaseRuntime) GetIgnoreErr() bool {
 	return b.ignoreErr
 }
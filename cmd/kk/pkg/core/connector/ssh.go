@@ -51,6 +51,11 @@ type Cfg struct {
 	Bastion     string
 	BastionPort int
 	BastionUser string
+	// HostKeyCallback verifies the host keys of the target host and the bastions.
+	HostKeyCallback ssh.HostKeyCallback
+	// Bastions is the chain of jump hosts, ordered from the first hop to the last hop. A bastion without
+	// any credentials uses the credentials of the target host.
+	Bastions []Bastion
 }
 
 const socketEnvPrefix = "env:"
@@ -59,8 +64,10 @@ type connection struct {
 	mu         sync.Mutex
 	sftpclient *sftp.Client
 	sshclient  *ssh.Client
-	ctx        context.Context
-	cancel     context.CancelFunc
+	// bastionclients are the clients of the jump hosts, they are closed after the target client.
+	bastionclients []*ssh.Client
+	ctx            context.Context
+	cancel         context.CancelFunc
 }
 
 func NewConnection(cfg Cfg) (Connection, error) {
@@ -69,25 +76,101 @@ func NewConnection(cfg Cfg) (Connection, error) {
 		return nil, errors.Wrap(err, "Failed to validate ssh connection parameters")
 	}
 
+	authMethods, err := newAuthMethods(cfg.Password, cfg.PrivateKey, cfg.AgentSocket)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            cfg.Username,
+		Timeout:         cfg.Timeout,
+		Auth:            authMethods,
+		HostKeyCallback: cfg.HostKeyCallback,
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	sshConn := &connection{
+		ctx:    ctx,
+		cancel: cancelFn,
+	}
+
+	var client *ssh.Client
+	for _, bastion := range cfg.Bastions {
+		bastionConfig := *sshConfig
+		if len(bastion.Password) > 0 || len(bastion.PrivateKey) > 0 {
+			bastionAuth, err := newAuthMethods(bastion.Password, bastion.PrivateKey, "")
+			if err != nil {
+				sshConn.Close()
+				return nil, errors.Wrapf(err, "invalid credentials of bastion %s", bastion.Address)
+			}
+			bastionConfig.Auth = bastionAuth
+		}
+		bastionConfig.User = bastion.User
+		bastionConfig.Timeout = time.Duration(bastion.Timeout) * time.Second
+
+		endpoint := net.JoinHostPort(bastion.Address, strconv.Itoa(bastion.Port))
+		client, err = dial(client, endpoint, &bastionConfig)
+		if err != nil {
+			sshConn.Close()
+			return nil, errors.Wrapf(err, "could not establish connection to bastion %s", endpoint)
+		}
+		sshConn.bastionclients = append(sshConn.bastionclients, client)
+	}
+
+	endpoint := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
+	sshConn.sshclient, err = dial(client, endpoint, sshConfig)
+	if err != nil {
+		sshConn.Close()
+		return nil, errors.Wrapf(err, "could not establish connection to %s", endpoint)
+	}
+
+	sftpClient, err := sftp.NewClient(sshConn.sshclient)
+	if err != nil {
+		sshConn.Close()
+		return nil, errors.Wrapf(err, "new sftp client failed: %v", err)
+	}
+	sshConn.sftpclient = sftpClient
+	return sshConn, nil
+}
+
+// dial connects to the endpoint directly if the jump client is nil, otherwise through the jump client.
+func dial(jump *ssh.Client, endpoint string, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	if jump == nil {
+		return ssh.Dial("tcp", endpoint, sshConfig)
+	}
+
+	conn, err := jump.Dial("tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ncc, chans, reqs, err := ssh.NewClientConn(conn, endpoint, sshConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(ncc, chans, reqs), nil
+}
+
+func newAuthMethods(password, privateKey, agentSocket string) ([]ssh.AuthMethod, error) {
 	authMethods := make([]ssh.AuthMethod, 0)
 
-	if len(cfg.Password) > 0 {
-		authMethods = append(authMethods, ssh.Password(cfg.Password))
+	if len(password) > 0 {
+		authMethods = append(authMethods, ssh.Password(password))
 	}
 
-	if len(cfg.PrivateKey) > 0 {
-		signer, parseErr := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+	if len(privateKey) > 0 {
+		signer, parseErr := ssh.ParsePrivateKey([]byte(privateKey))
 		if parseErr != nil {
 			return nil, errors.Wrap(parseErr, "The given SSH key could not be parsed")
 		}
 		authMethods = append(authMethods, ssh.PublicKeys(signer))
 	}
 
-	if len(cfg.AgentSocket) > 0 {
-		addr := cfg.AgentSocket
+	if len(agentSocket) > 0 {
+		addr := agentSocket
 
-		if strings.HasPrefix(cfg.AgentSocket, socketEnvPrefix) {
-			envName := strings.TrimPrefix(cfg.AgentSocket, socketEnvPrefix)
+		if strings.HasPrefix(agentSocket, socketEnvPrefix) {
+			envName := strings.TrimPrefix(agentSocket, socketEnvPrefix)
 
 			if envAddr := os.Getenv(envName); len(envAddr) > 0 {
 				addr = envAddr
@@ -109,66 +192,7 @@ func NewConnection(cfg Cfg) (Connection, error) {
 
 		authMethods = append(authMethods, ssh.PublicKeys(signers...))
 	}
-
-	sshConfig := &ssh.ClientConfig{
-		User:            cfg.Username,
-		Timeout:         cfg.Timeout,
-		Auth:            authMethods,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-
-	targetHost := cfg.Address
-	targetPort := strconv.Itoa(cfg.Port)
-
-	if cfg.Bastion != "" {
-		targetHost = cfg.Bastion
-		targetPort = strconv.Itoa(cfg.BastionPort)
-		sshConfig.User = cfg.BastionUser
-	}
-
-	endpoint := net.JoinHostPort(targetHost, targetPort)
-
-	client, err := ssh.Dial("tcp", endpoint, sshConfig)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not establish connection to %s", endpoint)
-	}
-
-	ctx, cancelFn := context.WithCancel(context.Background())
-	sshConn := &connection{
-		ctx:    ctx,
-		cancel: cancelFn,
-	}
-
-	if cfg.Bastion == "" {
-		sshConn.sshclient = client
-		sftpClient, err := sftp.NewClient(sshConn.sshclient)
-		if err != nil {
-			return nil, errors.Wrapf(err, "new sftp client failed: %v", err)
-		}
-		sshConn.sftpclient = sftpClient
-		return sshConn, nil
-	}
-
-	endpointBehindBastion := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
-
-	conn, err := client.Dial("tcp", endpointBehindBastion)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
-	}
-
-	sshConfig.User = cfg.Username
-	ncc, chans, reqs, err := ssh.NewClientConn(conn, endpointBehindBastion, sshConfig)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
-	}
-
-	sshConn.sshclient = ssh.NewClient(ncc, chans, reqs)
-	sftpClient, err := sftp.NewClient(sshConn.sshclient)
-	if err != nil {
-		return nil, errors.Wrapf(err, "new sftp client failed: %v", err)
-	}
-	sshConn.sftpclient = sftpClient
-	return sshConn, nil
+	return authMethods, nil
 }
 
 func validateOptions(cfg Cfg) (Cfg, error) {
@@ -184,6 +208,10 @@ func validateOptions(cfg Cfg) (Cfg, error) {
 		return cfg, errors.New("Must specify at least one of password, private key, keyfile or agent socket")
 	}
 
+	if cfg.HostKeyCallback == nil {
+		return cfg, errors.New("No host key callback specified for SSH connection")
+	}
+
 	if len(cfg.PrivateKey) == 0 && len(cfg.KeyFile) > 0 {
 		content, err := ioutil.ReadFile(cfg.KeyFile)
 		if err != nil {
@@ -210,6 +238,41 @@ func validateOptions(cfg Cfg) (Cfg, error) {
 		cfg.Timeout = 15 * time.Second
 	}
 
+	if cfg.Bastion != "" && len(cfg.Bastions) == 0 {
+		cfg.Bastions = []Bastion{{Address: cfg.Bastion, Port: cfg.BastionPort, User: cfg.BastionUser}}
+	}
+
+	bastions := make([]Bastion, 0, len(cfg.Bastions))
+	for _, bastion := range cfg.Bastions {
+		if len(bastion.Address) == 0 {
+			return cfg, errors.New("No address specified for SSH bastion")
+		}
+
+		if len(bastion.PrivateKey) == 0 && len(bastion.PrivateKeyPath) > 0 {
+			content, err := ioutil.ReadFile(bastion.PrivateKeyPath)
+			if err != nil {
+				return cfg, errors.Wrapf(err, "Failed to read keyfile %q of bastion %s", bastion.PrivateKeyPath, bastion.Address)
+			}
+
+			bastion.PrivateKey = string(content)
+			bastion.PrivateKeyPath = ""
+		}
+
+		if bastion.Port <= 0 {
+			bastion.Port = 22
+		}
+
+		if bastion.User == "" {
+			bastion.User = cfg.Username
+		}
+
+		if bastion.Timeout <= 0 {
+			bastion.Timeout = int64(cfg.Timeout / time.Second)
+		}
+		bastions = append(bastions, bastion)
+	}
+	cfg.Bastions = bastions
+
 	return cfg, nil
 }
 
@@ -217,7 +280,7 @@ func (c *connection) Close() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.sshclient == nil && c.sftpclient == nil {
+	if c.sshclient == nil && c.sftpclient == nil && len(c.bastionclients) == 0 {
 		return
 	}
 	c.cancel()
@@ -230,6 +293,10 @@ func (c *connection) Close() {
 		c.sftpclient.Close()
 		c.sftpclient = nil
 	}
+	for i := len(c.bastionclients) - 1; i >= 0; i-- {
+		c.bastionclients[i].Close()
+	}
+	c.bastionclients = nil
 }
 
 func (c *connection) session() (*ssh.Session, error) {
@@ -24,6 +24,7 @@ import (
 
 type ModuleResult struct {
 	HostResults   map[string]Interface
+	TaskResults   []*TaskResult
 	CombineResult error
 	Status        ResultStatus
 	StartTime     time.Time
@@ -48,6 +49,10 @@ func (m *ModuleResult) AppendHostResult(p Interface) {
 	m.HostResults[p.GetHost().GetName()] = p
 }
 
+func (m *ModuleResult) AppendTaskResult(t *TaskResult) {
+	m.TaskResults = append(m.TaskResults, t)
+}
+
 func (m *ModuleResult) LocalErrResult(err error) {
 	now := time.Now()
 	r := &ActionResult{
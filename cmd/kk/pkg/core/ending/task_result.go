@@ -26,14 +26,15 @@ import (
 
 type TaskResult struct {
 	mu            sync.Mutex
+	Name          string
 	ActionResults []*ActionResult
 	Status        ResultStatus
 	StartTime     time.Time
 	EndTime       time.Time
 }
 
-func NewTaskResult() *TaskResult {
-	return &TaskResult{ActionResults: make([]*ActionResult, 0, 0), Status: NULL, StartTime: time.Now()}
+func NewTaskResult(name string) *TaskResult {
+	return &TaskResult{Name: name, ActionResults: make([]*ActionResult, 0, 0), Status: NULL, StartTime: time.Now()}
 }
 
 func (t *TaskResult) ErrResult() {
@@ -23,6 +23,7 @@ import (
 )
 
 type Module interface {
+	GetName() string
 	IsSkip() bool
 	Default(runtime connector.Runtime, pipelineCache *cache.Cache, moduleCache *cache.Cache)
 	Init()
@@ -34,3 +35,10 @@ type Module interface {
 	AppendPostHook(h PostHookInterface)
 	CallPostHook(result *ending.ModuleResult) error
 }
+
+// StatefulModule is implemented by the modules that collect the state (cluster status, binaries, etc.)
+// into the caches. They are never skipped when a pipeline is resumed from a checkpoint.
+type StatefulModule interface {
+	Module
+	IsStateful() bool
+}
@@ -17,6 +17,7 @@
 package module
 
 import (
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/ending"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
@@ -40,12 +41,18 @@ func (b *BaseTaskModule) Is() string {
 }
 
 func (b *BaseTaskModule) Run(result *ending.ModuleResult) {
+	if connector.IsDryRun(b.Runtime.(connector.Runtime).GetConnector()) {
+		b.dryRun(result)
+		return
+	}
+
 	for i := range b.Tasks {
 		t := b.Tasks[i]
 		t.Init(b.Runtime.(connector.Runtime), b.ModuleCache, b.PipelineCache)
 
 		logger.Log.Infof("[%s] %s", b.Name, t.GetDesc())
 		res := t.Execute()
+		result.AppendTaskResult(res)
 		for j := range res.ActionResults {
 			ac := res.ActionResults[j]
 			logger.Log.Infof("%s: [%s]", ac.Status.String(), ac.Host.GetName())
@@ -74,3 +81,31 @@ func (b *BaseTaskModule) Run(result *ending.ModuleResult) {
 	}
 	result.NormalResult()
 }
+
+// dryRun executes the remote tasks against the recording connections without retry, and never runs the local tasks
+// because they act on the local machine directly. A failed task doesn't stop the module, its error is kept in the result.
+func (b *BaseTaskModule) dryRun(result *ending.ModuleResult) {
+	for i := range b.Tasks {
+		t := b.Tasks[i]
+		if r, ok := t.(*task.RemoteTask); ok {
+			r.Retry = 1
+		}
+		t.Init(b.Runtime.(connector.Runtime), b.ModuleCache, b.PipelineCache)
+
+		logger.Log.Infof("[%s] %s (dry-run)", b.Name, t.GetDesc())
+		if _, ok := t.(*task.LocalTask); ok {
+			res := ending.NewTaskResult(t.GetName())
+			res.AppendSkip(&connector.BaseHost{Name: common.LocalHost})
+			res.SkippedResult()
+			result.AppendTaskResult(res)
+			continue
+		}
+
+		res := t.Execute()
+		result.AppendTaskResult(res)
+		for j := range res.ActionResults {
+			result.AppendHostResult(res.ActionResults[j])
+		}
+	}
+	result.NormalResult()
+}
@@ -0,0 +1,162 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+const checkpointDir = "checkpoints"
+
+// Checkpoint records the modules of a pipeline which have been executed successfully,
+// so that a failed pipeline can be resumed from the first unfinished module.
+type Checkpoint struct {
+	Pipeline   string             `json:"pipeline"`
+	ConfigHash string             `json:"configHash"`
+	Modules    []ModuleCheckpoint `json:"modules"`
+
+	path string
+}
+
+type ModuleCheckpoint struct {
+	Index  int              `json:"index"`
+	Type   string           `json:"type"`
+	Status string           `json:"status"`
+	Tasks  []TaskCheckpoint `json:"tasks,omitempty"`
+}
+
+// TaskCheckpoint records the status of a task per host, it shows where a failed module stopped.
+type TaskCheckpoint struct {
+	Name  string            `json:"name"`
+	Hosts map[string]string `json:"hosts"`
+}
+
+func checkpointPath(workDir, objName, pipelineName string) string {
+	return filepath.Join(workDir, checkpointDir, fmt.Sprintf("%s-%s.json", objName, pipelineName))
+}
+
+// LoadCheckpoint reads the checkpoint file of the pipeline. A new empty checkpoint is returned if the file
+// does not exist or it was recorded with another config hash.
+func LoadCheckpoint(workDir, objName, pipelineName, configHash string) (*Checkpoint, error) {
+	c := &Checkpoint{
+		Pipeline:   pipelineName,
+		ConfigHash: configHash,
+		Modules:    make([]ModuleCheckpoint, 0),
+		path:       checkpointPath(workDir, objName, pipelineName),
+	}
+	if !util.IsExist(c.path) {
+		return c, nil
+	}
+
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read checkpoint file %s failed", c.path)
+	}
+	saved := &Checkpoint{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal checkpoint file %s failed", c.path)
+	}
+	if saved.Pipeline != pipelineName || saved.ConfigHash != configHash {
+		return c, nil
+	}
+	c.Modules = saved.Modules
+	return c, nil
+}
+
+// IsCompleted reports whether the module at the given index has been executed successfully.
+func (c *Checkpoint) IsCompleted(index int, m module.Module) bool {
+	for _, mc := range c.Modules {
+		if mc.Index == index && mc.Type == moduleType(m) && mc.Status == ending.SUCCESS.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Record stores the task results of the module per host and persists the checkpoint into the work dir.
+func (c *Checkpoint) Record(index int, m module.Module, result *ending.ModuleResult) error {
+	mc := ModuleCheckpoint{
+		Index:  index,
+		Type:   moduleType(m),
+		Status: result.Status.String(),
+		Tasks:  make([]TaskCheckpoint, 0, len(result.TaskResults)),
+	}
+	for _, t := range result.TaskResults {
+		tc := TaskCheckpoint{Name: t.Name, Hosts: make(map[string]string)}
+		for _, ac := range t.ActionResults {
+			if ac.Host == nil {
+				continue
+			}
+			tc.Hosts[ac.Host.GetName()] = ac.Status.String()
+		}
+		mc.Tasks = append(mc.Tasks, tc)
+	}
+
+	modules := make([]ModuleCheckpoint, 0, len(c.Modules)+1)
+	for _, old := range c.Modules {
+		if old.Index != index {
+			modules = append(modules, old)
+		}
+	}
+	c.Modules = append(modules, mc)
+	return c.save()
+}
+
+// Remove deletes the checkpoint file, it's called after the whole pipeline executed successfully.
+func (c *Checkpoint) Remove() error {
+	if !util.IsExist(c.path) {
+		return nil
+	}
+	return os.Remove(c.path)
+}
+
+func (c *Checkpoint) save() error {
+	if err := util.CreateDir(filepath.Dir(c.path)); err != nil {
+		return errors.Wrap(err, "create checkpoint dir failed")
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal checkpoint failed")
+	}
+	if err := ioutil.WriteFile(c.path, data, 0644); err != nil {
+		return errors.Wrapf(err, "write checkpoint file %s failed", c.path)
+	}
+	return nil
+}
+
+func moduleType(m module.Module) string {
+	return fmt.Sprintf("%T", m)
+}
+
+// moduleName returns the name of a module which has not been initialized, e.g. "etcd.BackupModule".
+func moduleName(m module.Module) string {
+	if m.GetName() != "" {
+		return m.GetName()
+	}
+	return strings.TrimPrefix(moduleType(m), "*")
+}
@@ -0,0 +1,132 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+type statefulModule struct {
+	module.BaseTaskModule
+}
+
+func (s *statefulModule) IsStateful() bool {
+	return true
+}
+
+func TestCheckpoint(t *testing.T) {
+	workDir := t.TempDir()
+	c, err := LoadCheckpoint(workDir, "cluster", "CreateClusterPipeline", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Modules) != 0 {
+		t.Fatalf("a new checkpoint has %d modules", len(c.Modules))
+	}
+
+	success := ending.NewModuleResult()
+	success.NormalResult()
+	failed := ending.NewModuleResult()
+	task := ending.NewTaskResult("GenerateCerts")
+	task.AppendSuccess(&connector.BaseHost{Name: "node1"})
+	task.AppendErr(&connector.BaseHost{Name: "node2"}, errors.New("failed"))
+	failed.AppendTaskResult(task)
+	failed.ErrResult(errors.New("failed"))
+	if err := c.Record(0, &module.BaseTaskModule{}, success); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Record(1, &module.CustomModule{}, failed); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadCheckpoint(workDir, "cluster", "CreateClusterPipeline", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		index int
+		m     module.Module
+		want  bool
+	}{
+		{name: "completed", index: 0, m: &module.BaseTaskModule{}, want: true},
+		{name: "failed", index: 1, m: &module.CustomModule{}, want: false},
+		{name: "another module at the index", index: 0, m: &module.CustomModule{}, want: false},
+		{name: "not executed", index: 2, m: &module.BaseTaskModule{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := saved.IsCompleted(tt.index, tt.m); got != tt.want {
+				t.Errorf("IsCompleted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	wantTasks := []TaskCheckpoint{{Name: "GenerateCerts", Hosts: map[string]string{"node1": ending.SUCCESS.String(), "node2": ending.FAILED.String()}}}
+	if got := saved.Modules[1].Tasks; !reflect.DeepEqual(got, wantTasks) {
+		t.Errorf("the tasks of the failed module = %+v, want %+v", got, wantTasks)
+	}
+
+	changed, err := LoadCheckpoint(workDir, "cluster", "CreateClusterPipeline", "another-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed.Modules) != 0 {
+		t.Errorf("the checkpoint of another config hash is loaded: %+v", changed.Modules)
+	}
+
+	if err := saved.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if util.IsExist(checkpointPath(workDir, "cluster", "CreateClusterPipeline")) {
+		t.Errorf("the checkpoint file is not removed")
+	}
+}
+
+func TestSkipCompleted(t *testing.T) {
+	success := ending.NewModuleResult()
+	success.NormalResult()
+	c, err := LoadCheckpoint(t.TempDir(), "cluster", "CreateClusterPipeline", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Record(0, &module.BaseTaskModule{}, success); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Record(1, &statefulModule{}, success); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Pipeline{Resume: true, checkpoint: c}
+	if !p.skipCompleted(0, &module.BaseTaskModule{}) {
+		t.Errorf("the completed module is not skipped")
+	}
+	if p.skipCompleted(1, &statefulModule{}) {
+		t.Errorf("the stateful module is skipped")
+	}
+	p.Resume = false
+	if p.skipCompleted(0, &module.BaseTaskModule{}) {
+		t.Errorf("the completed module is skipped without resume")
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/ending"
+)
+
+// printPlan prints the modules and tasks that would be executed on each host, followed by the operations
+// recorded by the dry-run connections and the content of the rendered files.
+func (p *Pipeline) printPlan(dialer *connector.DryRunDialer) {
+	fmt.Printf("\nPipeline[%s] dry-run plan:\n\n", p.Name)
+	for _, m := range p.report.Modules {
+		if m.Status == ending.SKIPPED.String() {
+			fmt.Printf("Module[%s]: skipped\n", m.Name)
+			continue
+		}
+		fmt.Printf("Module[%s]\n", m.Name)
+		for _, t := range m.Tasks {
+			fmt.Printf("  Task[%s]\n", t.Name)
+			for _, h := range t.Hosts {
+				fmt.Printf("    %s: %s\n", h.Host, planStatus(h))
+			}
+		}
+	}
+
+	for _, host := range dialer.Hosts() {
+		fmt.Printf("\nHost[%s] operations:\n", host)
+		rendered := make([]connector.Record, 0)
+		for _, r := range dialer.Records(host) {
+			switch r.Type {
+			case connector.RecordExec:
+				fmt.Printf("  [exec]  %s\n", r.Command)
+			case connector.RecordScp:
+				fmt.Printf("  [copy]  %s -> %s\n", r.Local, remotePath(r.Remote))
+				if r.Content != "" {
+					rendered = append(rendered, r)
+				}
+			case connector.RecordFetch:
+				fmt.Printf("  [fetch] %s -> %s\n", r.Remote, r.Local)
+			case connector.RecordMkdir:
+				fmt.Printf("  [mkdir] %s\n", r.Remote)
+			}
+		}
+
+		for _, r := range rendered {
+			fmt.Printf("\n--- Host[%s] %s\n%s\n", host, remotePath(r.Remote), strings.TrimRight(r.Content, "\n"))
+		}
+	}
+	fmt.Println()
+}
+
+// planStatus describes what would happen on a host in dry-run mode.
+func planStatus(h HostReport) string {
+	switch h.Status {
+	case ending.SUCCESS.String():
+		return "run"
+	case ending.SKIPPED.String():
+		if h.Host == common.LocalHost {
+			return "local task, not executed in dry-run mode"
+		}
+		return "skip"
+	default:
+		return fmt.Sprintf("unknown, the task can not be evaluated without executing the previous tasks: %s", h.Error)
+	}
+}
+
+// remotePath returns the final destination of a file which is copied to the temporary dir before being moved.
+func remotePath(remote string) string {
+	tmpDir := filepath.Clean(common.TmpDir)
+	if strings.HasPrefix(remote, tmpDir+"/") {
+		return strings.TrimPrefix(remote, tmpDir)
+	}
+	return remote
+}
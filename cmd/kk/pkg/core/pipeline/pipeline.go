@@ -51,12 +51,35 @@ type Pipeline struct {
 	PipelineCache   *cache.Cache
 	ModuleCachePool sync.Pool
 	ModulePostHooks []module.PostHookInterface
+	// Quiet doesn't print the logo, for the pipelines whose stdout is a machine-readable output.
+	Quiet bool
+
+	// ConfigHash identifies the inputs of the pipeline. The progress of the pipeline is recorded into
+	// a checkpoint file only if it's set.
+	ConfigHash string
+	// Resume skips the modules which have been completed by a previous run with the same ConfigHash.
+	Resume     bool
+	checkpoint *Checkpoint
+	report     *Report
 }
 
 func (p *Pipeline) Init() error {
-	fmt.Print(logo)
+	if !p.Quiet {
+		fmt.Print(logo)
+	}
 	p.PipelineCache = cache.NewCache()
 	p.SpecHosts = len(p.Runtime.GetAllHosts())
+	p.report = NewReport(p.Name)
+	if p.ConfigHash != "" && !connector.IsDryRun(p.Runtime.GetConnector()) {
+		c, err := LoadCheckpoint(p.Runtime.GetWorkDir(), p.Runtime.GetObjName(), p.Name, p.ConfigHash)
+		if err != nil {
+			return err
+		}
+		if !p.Resume {
+			c.Modules = c.Modules[:0]
+		}
+		p.checkpoint = c
+	}
 	//if err := p.Runtime.GenerateWorkDir(); err != nil {
 	//	return err
 	//}
@@ -66,13 +89,23 @@ func (p *Pipeline) Init() error {
 	return nil
 }
 
-func (p *Pipeline) Start() error {
+func (p *Pipeline) Start() (err error) {
+	defer func() {
+		p.writeReport(err)
+	}()
+
 	if err := p.Init(); err != nil {
 		return errors.Wrapf(err, "Pipeline[%s] execute failed", p.Name)
 	}
 	for i := range p.Modules {
 		m := p.Modules[i]
 		if m.IsSkip() {
+			p.report.AppendSkippedModule(moduleName(m))
+			continue
+		}
+		if p.skipCompleted(i, m) {
+			logger.Log.Infof("skip %s, it has been completed in the previous run", moduleType(m))
+			p.report.AppendSkippedModule(moduleName(m))
 			continue
 		}
 
@@ -85,6 +118,12 @@ func (p *Pipeline) Start() error {
 		}
 
 		res := p.RunModule(m)
+		p.report.AppendModule(m.GetName(), res)
+		if p.checkpoint != nil {
+			if err := p.checkpoint.Record(i, m, res); err != nil {
+				logger.Log.Warnf("record checkpoint failed: %s", err)
+			}
+		}
 		err := m.CallPostHook(res)
 		if res.IsFailed() {
 			return errors.Wrapf(res.CombineResult, "Pipeline[%s] execute failed", p.Name)
@@ -101,9 +140,19 @@ func (p *Pipeline) Start() error {
 		p.Runtime.GetConnector().Close(host)
 	}
 
+	if dialer, ok := p.Runtime.GetConnector().(*connector.DryRunDialer); ok {
+		p.printPlan(dialer)
+		return nil
+	}
+
 	if p.SpecHosts != len(p.Runtime.GetAllHosts()) {
 		return errors.Errorf("Pipeline[%s] execute failed: there are some error in your spec hosts", p.Name)
 	}
+	if p.checkpoint != nil {
+		if err := p.checkpoint.Remove(); err != nil {
+			logger.Log.Warnf("remove checkpoint failed: %s", err)
+		}
+	}
 	logger.Log.Infof("Pipeline[%s] execute successfully", p.Name)
 	return nil
 }
@@ -121,6 +170,10 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 			}
 
 		case module.GoroutineModuleType:
+			if connector.IsDryRun(p.Runtime.GetConnector()) {
+				m.Run(result)
+				break
+			}
 			go func() {
 				m.Run(result)
 				if result.IsFailed() {
@@ -134,6 +187,11 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 			}
 		}
 
+		// the loop condition relies on the results of the actions, so the module only runs once in dry-run mode.
+		if connector.IsDryRun(p.Runtime.GetConnector()) {
+			break
+		}
+
 		stop, err := m.Until()
 		if err != nil {
 			result.LocalErrResult(err)
@@ -146,6 +204,31 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 	return result
 }
 
+func (p *Pipeline) writeReport(err error) {
+	path := p.Runtime.GetReportFile()
+	if path == "" || p.report == nil {
+		return
+	}
+	p.report.Finish(err)
+	if e := WriteReportFile(path, p.report); e != nil {
+		logger.Log.Warnf("write report failed: %s", e)
+		return
+	}
+	logger.Log.Infof("The report of Pipeline[%s] has been written to %s", p.Name, path)
+}
+
+// skipCompleted reports whether the module can be skipped when resuming the pipeline.
+// The stateful modules are always executed because the following modules rely on the state they collect.
+func (p *Pipeline) skipCompleted(index int, m module.Module) bool {
+	if !p.Resume || p.checkpoint == nil {
+		return false
+	}
+	if s, ok := m.(module.StatefulModule); ok && s.IsStateful() {
+		return false
+	}
+	return p.checkpoint.IsCompleted(index, m)
+}
+
 func (p *Pipeline) newModuleCache() *cache.Cache {
 	moduleCache, ok := p.ModuleCachePool.Get().(*cache.Cache)
 	if ok {
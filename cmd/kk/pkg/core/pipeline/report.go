@@ -0,0 +1,326 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+)
+
+// Report is the machine-readable result of a pipeline: pipeline -> module -> task -> host.
+// The durations are in seconds.
+type Report struct {
+	Pipeline  string         `json:"pipeline"`
+	Status    string         `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+	Duration  float64        `json:"duration"`
+	Modules   []ModuleReport `json:"modules"`
+}
+
+type ModuleReport struct {
+	Name      string       `json:"name"`
+	Status    string       `json:"status"`
+	Error     string       `json:"error,omitempty"`
+	StartTime time.Time    `json:"startTime"`
+	EndTime   time.Time    `json:"endTime"`
+	Duration  float64      `json:"duration"`
+	Tasks     []TaskReport `json:"tasks"`
+}
+
+type TaskReport struct {
+	Name      string       `json:"name"`
+	Status    string       `json:"status"`
+	StartTime time.Time    `json:"startTime"`
+	EndTime   time.Time    `json:"endTime"`
+	Duration  float64      `json:"duration"`
+	Hosts     []HostReport `json:"hosts"`
+}
+
+type HostReport struct {
+	Host      string    `json:"host"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Duration  float64   `json:"duration"`
+}
+
+func NewReport(pipelineName string) *Report {
+	return &Report{
+		Pipeline:  pipelineName,
+		Status:    ending.NULL.String(),
+		StartTime: time.Now(),
+		Modules:   make([]ModuleReport, 0),
+	}
+}
+
+// AppendModule converts the module result and its task results into the report.
+func (r *Report) AppendModule(name string, result *ending.ModuleResult) {
+	mr := ModuleReport{
+		Name:      name,
+		Status:    result.Status.String(),
+		Error:     errString(result.CombineResult),
+		StartTime: result.StartTime,
+		EndTime:   endTime(result.EndTime),
+		Tasks:     make([]TaskReport, 0, len(result.TaskResults)),
+	}
+	mr.Duration = duration(mr.StartTime, mr.EndTime)
+
+	for _, t := range result.TaskResults {
+		tr := TaskReport{
+			Name:      t.Name,
+			Status:    t.Status.String(),
+			StartTime: t.StartTime,
+			EndTime:   endTime(t.EndTime),
+			Hosts:     make([]HostReport, 0, len(t.ActionResults)),
+		}
+		tr.Duration = duration(tr.StartTime, tr.EndTime)
+
+		for _, ac := range t.ActionResults {
+			hr := HostReport{
+				Status:    ac.Status.String(),
+				Error:     errString(ac.Error),
+				StartTime: ac.StartTime,
+				EndTime:   ac.EndTime,
+				Duration:  duration(ac.StartTime, ac.EndTime),
+			}
+			if ac.Host != nil {
+				hr.Host = ac.Host.GetName()
+			}
+			tr.Hosts = append(tr.Hosts, hr)
+		}
+		mr.Tasks = append(mr.Tasks, tr)
+	}
+	r.Modules = append(r.Modules, mr)
+}
+
+// AppendSkippedModule records a module which is not executed.
+func (r *Report) AppendSkippedModule(name string) {
+	r.Modules = append(r.Modules, ModuleReport{
+		Name:   name,
+		Status: ending.SKIPPED.String(),
+		Tasks:  make([]TaskReport, 0),
+	})
+}
+
+// Finish records the final status of the pipeline.
+func (r *Report) Finish(err error) {
+	r.EndTime = time.Now()
+	r.Duration = duration(r.StartTime, r.EndTime)
+	if err != nil {
+		r.Status = ending.FAILED.String()
+		r.Error = err.Error()
+		return
+	}
+	r.Status = ending.SUCCESS.String()
+}
+
+// Reports are the reports of the pipelines run by a command, e.g. creating a cluster and then deploying KubeSphere,
+// they are written into the same report file.
+type Reports struct {
+	Pipelines []*Report `json:"pipelines"`
+}
+
+var (
+	reportsMu sync.Mutex
+	// written holds the reports written by this process per report file.
+	written = make(map[string]*Reports)
+)
+
+// WriteReportFile adds the report to the ones written into the file by the former pipelines of the command,
+// and writes all of them. The file left by another command is overwritten.
+func WriteReportFile(path string, r *Report) error {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+
+	reports := &Reports{}
+	if w, ok := written[path]; ok {
+		reports.Pipelines = append(reports.Pipelines, w.Pipelines...)
+	}
+	if !reports.contains(r) {
+		reports.Pipelines = append(reports.Pipelines, r)
+	}
+	if err := reports.WriteFile(path); err != nil {
+		return err
+	}
+	written[path] = reports
+	return nil
+}
+
+func (rs *Reports) contains(r *Report) bool {
+	for _, p := range rs.Pipelines {
+		if p == r {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteFile writes the reports as JUnit XML if the path ends with ".xml", otherwise as JSON.
+func (rs *Reports) WriteFile(path string) error {
+	var (
+		data []byte
+		err  error
+	)
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		data, err = xml.MarshalIndent(rs.JUnit(), "", "  ")
+		data = append([]byte(xml.Header), data...)
+	} else {
+		data, err = json.MarshalIndent(rs, "", "  ")
+	}
+	if err != nil {
+		return errors.Wrap(err, "marshal report failed")
+	}
+
+	if err := util.CreateDir(filepath.Dir(path)); err != nil {
+		return errors.Wrap(err, "create report dir failed")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "write report file %s failed", path)
+	}
+	return nil
+}
+
+// JUnit merges the test suites of all the pipelines, the test cases are told apart by the pipeline in their class names.
+func (rs *Reports) JUnit() *JUnitTestSuites {
+	suites := &JUnitTestSuites{}
+	var (
+		names []string
+		total float64
+	)
+	for _, r := range rs.Pipelines {
+		s := r.JUnit()
+		names = append(names, s.Name)
+		total += r.Duration
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Suites = append(suites.Suites, s.Suites...)
+	}
+	suites.Name = strings.Join(names, ",")
+	suites.Time = seconds(total)
+	return suites
+}
+
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// JUnit converts the report into JUnit test suites. Each module is a test suite, and each task on a host is a test case.
+func (r *Report) JUnit() *JUnitTestSuites {
+	suites := &JUnitTestSuites{
+		Name: r.Pipeline,
+		Time: seconds(r.Duration),
+	}
+	for _, m := range r.Modules {
+		suite := JUnitTestSuite{
+			Name: m.Name,
+			Time: seconds(m.Duration),
+		}
+		if !m.StartTime.IsZero() {
+			suite.Timestamp = m.StartTime.Format(time.RFC3339)
+		}
+		for _, t := range m.Tasks {
+			for _, h := range t.Hosts {
+				tc := JUnitTestCase{
+					Name:      fmt.Sprintf("%s [%s]", t.Name, h.Host),
+					ClassName: fmt.Sprintf("%s.%s", r.Pipeline, m.Name),
+					Time:      seconds(h.Duration),
+				}
+				switch h.Status {
+				case ending.FAILED.String():
+					tc.Failure = &JUnitFailure{Message: fmt.Sprintf("%s failed on %s", t.Name, h.Host), Content: h.Error}
+					suite.Failures++
+				case ending.SKIPPED.String():
+					tc.Skipped = &struct{}{}
+					suite.Skipped++
+				}
+				suite.TestCases = append(suite.TestCases, tc)
+				suite.Tests++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func endTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
+
+func duration(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start).Seconds()
+}
+
+func seconds(d float64) string {
+	return fmt.Sprintf("%.3f", d)
+}
@@ -0,0 +1,99 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/ending"
+)
+
+func TestReport_JUnit(t *testing.T) {
+	taskResult := ending.NewTaskResult("InstallBinaries")
+	taskResult.AppendSuccess(&connector.BaseHost{Name: "node1"})
+	taskResult.AppendSkip(&connector.BaseHost{Name: "node2"})
+	taskResult.AppendErr(&connector.BaseHost{Name: "node3"}, errors.New("scp failed"))
+	taskResult.ErrResult()
+
+	moduleResult := ending.NewModuleResult()
+	moduleResult.AppendTaskResult(taskResult)
+	moduleResult.ErrResult(errors.New("Module[BinariesModule] exec failed"))
+
+	r := NewReport("TestPipeline")
+	r.AppendModule("BinariesModule", moduleResult)
+	r.Finish(moduleResult.CombineResult)
+
+	if r.Status != ending.FAILED.String() {
+		t.Errorf("report status = %s, want %s", r.Status, ending.FAILED.String())
+	}
+	if len(r.Modules) != 1 || len(r.Modules[0].Tasks) != 1 || len(r.Modules[0].Tasks[0].Hosts) != 3 {
+		t.Fatalf("unexpected report structure: %+v", r.Modules)
+	}
+
+	suites := r.JUnit()
+	if suites.Tests != 3 {
+		t.Errorf("tests = %d, want 3", suites.Tests)
+	}
+	if suites.Failures != 1 {
+		t.Errorf("failures = %d, want 1", suites.Failures)
+	}
+	if suites.Suites[0].Skipped != 1 {
+		t.Errorf("skipped = %d, want 1", suites.Suites[0].Skipped)
+	}
+	failure := suites.Suites[0].TestCases[2].Failure
+	if failure == nil || failure.Content != "scp failed" {
+		t.Errorf("unexpected failure of node3: %+v", failure)
+	}
+}
+
+func TestWriteReportFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	create := NewReport("CreateClusterPipeline")
+	create.Finish(nil)
+	deploy := NewReport("DeployKubeSpherePipeline")
+	deploy.Finish(errors.New("timeout"))
+
+	for _, r := range []*Report{create, deploy, deploy} {
+		if err := WriteReportFile(path, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reports := &Reports{}
+	if err := json.Unmarshal(data, reports); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range reports.Pipelines {
+		got = append(got, fmt.Sprintf("%s:%s", r.Pipeline, r.Status))
+	}
+	want := []string{"CreateClusterPipeline:success", "DeployKubeSpherePipeline:failed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pipelines in the report = %v, want %v", got, want)
+	}
+}
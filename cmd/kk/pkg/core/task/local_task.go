@@ -52,6 +52,10 @@ type LocalTask struct {
 	TaskResult    *ending.TaskResult
 }
 
+func (l *LocalTask) GetName() string {
+	return l.Name
+}
+
 func (l *LocalTask) GetDesc() string {
 	return l.Desc
 }
@@ -64,10 +68,10 @@ func (l *LocalTask) Init(runtime connector.Runtime, moduleCache *cache.Cache, pi
 }
 
 func (l *LocalTask) Default() {
-	l.TaskResult = ending.NewTaskResult()
 	if l.Name == "" {
 		l.Name = DefaultTaskName
 	}
+	l.TaskResult = ending.NewTaskResult(l.Name)
 
 	if l.Prepare == nil {
 		l.Prepare = new(prepare.BasePrepare)
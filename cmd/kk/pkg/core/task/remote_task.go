@@ -55,6 +55,10 @@ type RemoteTask struct {
 	TaskResult    *ending.TaskResult
 }
 
+func (t *RemoteTask) GetName() string {
+	return t.Name
+}
+
 func (t *RemoteTask) GetDesc() string {
 	return t.Desc
 }
@@ -312,10 +316,10 @@ func (t *RemoteTask) RunRollback(runtime connector.Runtime, host connector.Host,
 }
 
 func (t *RemoteTask) Default() {
-	t.TaskResult = ending.NewTaskResult()
 	if t.Name == "" {
 		t.Name = DefaultTaskName
 	}
+	t.TaskResult = ending.NewTaskResult(t.Name)
 
 	if t.Prepare == nil {
 		t.Prepare = new(prepare.BasePrepare)
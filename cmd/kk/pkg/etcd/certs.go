@@ -148,6 +148,14 @@ func (g *GenerateCerts) Execute(runtime connector.Runtime) error {
 	altName := GenerateAltName(g.KubeConf, &runtime)
 
 	files := []string{"ca.pem", "ca-key.pem"}
+	if g.KubeConf.Cluster.Etcd.PKI.CA != nil {
+		if err := g.preparePKI(runtime, pkiPath); err != nil {
+			return err
+		}
+		if g.KubeConf.Cluster.Etcd.PKI.CA.KeyFile == "" {
+			files = []string{"ca.pem"}
+		}
+	}
 
 	// CA
 	certsList := []*certs.KubekeyCert{KubekeyCertEtcdCA()}
@@ -188,6 +196,63 @@ func (g *GenerateCerts) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// preparePKI writes the CA provided by the user into the pki dir, so that the certificates are signed by it.
+// In the external CA mode, the certificates issued by the CA are copied from the certs dir instead.
+func (g *GenerateCerts) preparePKI(runtime connector.Runtime, pkiPath string) error {
+	pki := g.KubeConf.Cluster.Etcd.PKI
+	caCert, certPEM, keyPEM, err := certs.ReadCertificateAuthorityFiles(pki.CA.CertFile, pki.CA.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	if inUse, err := certs.TryLoadCertFromDisk(pkiPath, "ca"); err == nil && !inUse.Equal(caCert) {
+		if v, ok := g.PipelineCache.Get(common.ETCDCluster); ok && v.(*EtcdCluster).clusterExist {
+			return errors.New("the CA of the etcd cluster is different from the provided one, the CA of a running cluster can not be replaced, set etcd.pki to the CA in use")
+		}
+		// the certificates left by a previous run are signed by another CA
+		if err := os.RemoveAll(pkiPath); err != nil {
+			return errors.Wrapf(err, "failed to remove dir %s", pkiPath)
+		}
+	}
+	if err := util.CreateDir(pkiPath); err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", pkiPath)
+	}
+	if err := ioutil.WriteFile(filepath.Join(pkiPath, "ca.pem"), certPEM, 0644); err != nil {
+		return errors.Wrap(err, "failed to write the etcd CA")
+	}
+	if keyPEM != nil {
+		if err := ioutil.WriteFile(filepath.Join(pkiPath, "ca-key.pem"), keyPEM, 0600); err != nil {
+			return errors.Wrap(err, "failed to write the etcd CA key")
+		}
+		return nil
+	}
+
+	if pki.CertsDir == "" {
+		return errors.New("etcd.pki.certsDir is required as the key of the etcd CA is not provided")
+	}
+	var names []string
+	for _, host := range runtime.GetAllHosts() {
+		if host.IsRole(common.ETCD) {
+			names = append(names, fmt.Sprintf("admin-%s", host.GetName()), fmt.Sprintf("member-%s", host.GetName()))
+		}
+		if host.IsRole(common.Master) {
+			names = append(names, fmt.Sprintf("node-%s", host.GetName()))
+		}
+	}
+	for _, name := range names {
+		for _, file := range []string{name + ".pem", name + "-key.pem"} {
+			data, err := ioutil.ReadFile(filepath.Join(pki.CertsDir, file))
+			if err != nil {
+				return errors.Wrapf(err, "%s is required as the key of the etcd CA is not provided", filepath.Join(pki.CertsDir, file))
+			}
+			if err := ioutil.WriteFile(filepath.Join(pkiPath, file), data, 0600); err != nil {
+				return errors.Wrapf(err, "failed to copy %s", file)
+			}
+		}
+	}
+	return nil
+}
+
 func GenerateAltName(k *common.KubeConf, runtime *connector.Runtime) *cert.AltNames {
 	var altName cert.AltNames
 
@@ -36,6 +36,10 @@ func (p *PreCheckModule) IsSkip() bool {
 	return p.Skip
 }
 
+func (p *PreCheckModule) IsStateful() bool {
+	return true
+}
+
 func (p *PreCheckModule) Init() {
 	p.Name = "ETCDPreCheckModule"
 	p.Desc = "Get ETCD cluster status"
@@ -433,3 +437,169 @@ func (b *BackupModule) Init() {
 		enable,
 	}
 }
+
+type RestoreModule struct {
+	common.KubeModule
+}
+
+func (r *RestoreModule) Init() {
+	r.Name = "ETCDRestoreModule"
+	r.Desc = "Restore ETCD cluster data from snapshot"
+
+	stopKubeApiserver := &task.RemoteTask{
+		Name:     "StopKubeApiserver",
+		Desc:     "Stop kube-apiserver",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StopKubeApiserver),
+		Parallel: true,
+	}
+
+	stopETCD := &task.RemoteTask{
+		Name:     "StopETCD",
+		Desc:     "Stop etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(StopETCD),
+		Parallel: true,
+	}
+
+	syncSnapshot := &task.RemoteTask{
+		Name:     "SyncETCDSnapshot",
+		Desc:     "Synchronize etcd snapshot",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(SyncSnapshot),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	restoreSnapshot := &task.RemoteTask{
+		Name:     "RestoreETCDSnapshot",
+		Desc:     "Restore etcd data from snapshot",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(RestoreSnapshot),
+		Parallel: true,
+	}
+
+	refreshETCDConfig := &task.RemoteTask{
+		Name:     "RefreshETCDConfig",
+		Desc:     "Refresh etcd.env config on all etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   &RefreshConfig{ToExisting: true},
+		Parallel: false,
+	}
+
+	startETCD := &task.RemoteTask{
+		Name:     "StartETCD",
+		Desc:     "Start etcd members in order",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(StartETCD),
+		Parallel: false,
+	}
+
+	accessAddress := &task.RemoteTask{
+		Name:     "GenerateAccessAddress",
+		Desc:     "Generate access address",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(GenerateAccessAddress),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	allETCDNodeHealthCheck := &task.RemoteTask{
+		Name:     "AllETCDNodeHealthCheck",
+		Desc:     "Health check on all etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(HealthCheck),
+		Parallel: true,
+		Retry:    20,
+	}
+
+	startKubeApiserver := &task.RemoteTask{
+		Name:     "StartKubeApiserver",
+		Desc:     "Start kube-apiserver",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StartKubeApiserver),
+		Parallel: true,
+	}
+
+	r.Tasks = []task.Interface{
+		stopKubeApiserver,
+		stopETCD,
+		syncSnapshot,
+		restoreSnapshot,
+		refreshETCDConfig,
+		startETCD,
+		accessAddress,
+		allETCDNodeHealthCheck,
+		startKubeApiserver,
+	}
+}
+
+type RemoveMemberModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RemoveMemberModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RemoveMemberModule) Init() {
+	r.Name = "ETCDRemoveMemberModule"
+	r.Desc = "Remove the deleted node from ETCD cluster"
+
+	nodeName, _ := r.PipelineCache.GetMustString("dstNode")
+	isMember := false
+	for _, host := range r.Runtime.GetHostsByRole(common.ETCD) {
+		if host.GetName() == nodeName {
+			isMember = true
+		}
+	}
+	if !isMember {
+		return
+	}
+
+	checkQuorum := &task.RemoteTask{
+		Name:     "CheckETCDQuorum",
+		Desc:     "Check the quorum of etcd after removing the member",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstRemainingETCDNode),
+		Action:   new(CheckQuorum),
+		Parallel: false,
+		Retry:    3,
+	}
+
+	removeMember := &task.RemoteTask{
+		Name:     "RemoveETCDMember",
+		Desc:     "Remove etcd member",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstRemainingETCDNode),
+		Action:   new(RemoveMember),
+		Parallel: false,
+	}
+
+	uninstallETCD := &task.RemoteTask{
+		Name:     "UninstallETCD",
+		Desc:     "Uninstall etcd on the deleted node",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(ETCDNodeToDelete),
+		Action:   new(UninstallETCD),
+		Parallel: false,
+	}
+
+	refreshETCDConfig := &task.RemoteTask{
+		Name:     "RefreshETCDConfig",
+		Desc:     "Refresh etcd.env config on the remaining etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  &ETCDNodeToDelete{Not: true},
+		Action:   &RefreshConfig{ToExisting: true},
+		Parallel: false,
+	}
+
+	r.Tasks = []task.Interface{
+		checkQuorum,
+		removeMember,
+		uninstallETCD,
+		refreshETCDConfig,
+	}
+}
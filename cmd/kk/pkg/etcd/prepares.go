@@ -58,3 +58,41 @@ func (n *NodeETCDExist) PreCheck(runtime connector.Runtime) (bool, error) {
 	}
 	return false, errors.New("get etcd node status by host label failed")
 }
+
+type ETCDNodeToDelete struct {
+	common.KubePrepare
+	Not bool
+}
+
+func (e *ETCDNodeToDelete) PreCheck(runtime connector.Runtime) (bool, error) {
+	nodeName, ok := e.PipelineCache.GetMustString("dstNode")
+	if !ok {
+		return false, errors.New("get dstNode failed by pipeline cache")
+	}
+	if runtime.RemoteHost().GetName() == nodeName {
+		return !e.Not, nil
+	}
+	return e.Not, nil
+}
+
+// FirstRemainingETCDNode is the first existing etcd member which is not going to be deleted,
+// the etcd members are managed through it.
+type FirstRemainingETCDNode struct {
+	common.KubePrepare
+}
+
+func (f *FirstRemainingETCDNode) PreCheck(runtime connector.Runtime) (bool, error) {
+	nodeName, ok := f.PipelineCache.GetMustString("dstNode")
+	if !ok {
+		return false, errors.New("get dstNode failed by pipeline cache")
+	}
+	for _, host := range runtime.GetHostsByRole(common.ETCD) {
+		if host.GetName() == nodeName {
+			continue
+		}
+		if exist, ok := host.GetCache().GetMustBool(common.ETCDExist); ok && exist {
+			return host.GetName() == runtime.RemoteHost().GetName(), nil
+		}
+	}
+	return false, errors.New("there is no other etcd member left after the node is deleted")
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/files"
 
@@ -28,7 +29,9 @@ import (
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/action"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/cache"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/logger"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/etcd/templates"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils"
@@ -418,3 +421,264 @@ func (e *EnableBackupETCDService) Execute(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+const (
+	kubeApiserverManifest        = "/etc/kubernetes/manifests/kube-apiserver.yaml"
+	stoppedKubeApiserverManifest = "/etc/kubernetes/kube-apiserver.yaml.etcd-restore"
+	etcdDataDir                  = "/var/lib/etcd"
+)
+
+// snapshotPath is where the snapshot is placed on the etcd nodes before restoring.
+var snapshotPath = filepath.Join(common.TmpDir, "etcd-snapshot.db")
+
+type StopKubeApiserver struct {
+	common.KubeAction
+}
+
+func (s *StopKubeApiserver) Execute(runtime connector.Runtime) error {
+	// The kube-apiserver static pod is stopped by kubelet after its manifest is moved out of the manifests dir.
+	stopCmd := fmt.Sprintf("if [ -f %s ]; then mv -f %s %s; fi", kubeApiserverManifest, kubeApiserverManifest, stoppedKubeApiserverManifest)
+	if _, err := runtime.GetRunner().SudoCmd(stopCmd, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "stop kube-apiserver failed")
+	}
+	return nil
+}
+
+type StartKubeApiserver struct {
+	common.KubeAction
+}
+
+func (s *StartKubeApiserver) Execute(runtime connector.Runtime) error {
+	startCmd := fmt.Sprintf("if [ -f %s ]; then mv -f %s %s; fi", stoppedKubeApiserverManifest, stoppedKubeApiserverManifest, kubeApiserverManifest)
+	if _, err := runtime.GetRunner().SudoCmd(startCmd, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "start kube-apiserver failed")
+	}
+	return nil
+}
+
+type StopETCD struct {
+	common.KubeAction
+}
+
+func (s *StopETCD) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd("systemctl stop etcd", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "stop etcd failed")
+	}
+	return nil
+}
+
+type SyncSnapshot struct {
+	common.KubeAction
+}
+
+// Execute uploads the snapshot if it's a local file, otherwise the snapshot must exist at the same path on every etcd node,
+// e.g. a snapshot in the backup dir.
+func (s *SyncSnapshot) Execute(runtime connector.Runtime) error {
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+
+	snapshot := s.KubeConf.Arg.EtcdSnapshot
+	if util.IsExist(snapshot) {
+		if err := runtime.GetRunner().Scp(snapshot, snapshotPath); err != nil {
+			return errors.Wrap(errors.WithStack(err), "sync etcd snapshot failed")
+		}
+		return nil
+	}
+
+	exist, err := runtime.GetRunner().FileExist(snapshot)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("etcd snapshot %s is not found on the local machine or the etcd node %s", snapshot, runtime.RemoteHost().GetName())
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("cp -f %s %s", snapshot, snapshotPath), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "copy etcd snapshot failed")
+	}
+	return nil
+}
+
+type RestoreSnapshot struct {
+	common.KubeAction
+}
+
+func (r *RestoreSnapshot) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if exist, ok := host.GetCache().GetMustBool(common.ETCDExist); !ok || !exist {
+		return fmt.Errorf("etcd is not installed on %s, only the existing members can be restored", host.GetName())
+	}
+	etcdName, ok := host.GetCache().GetMustString(common.ETCDName)
+	if !ok {
+		return errors.New("get etcd node status by host label failed")
+	}
+
+	v, ok := r.PipelineCache.Get(common.ETCDCluster)
+	if !ok {
+		return errors.New("get etcd cluster status by pipeline cache failed")
+	}
+	cluster := v.(*EtcdCluster)
+
+	// The old data dir is kept in case the restored data needs to be rolled back manually.
+	backupCmd := fmt.Sprintf("if [ -d %s ]; then mv %s %s-%s; fi",
+		etcdDataDir, etcdDataDir, etcdDataDir, time.Now().Format("20060102150405"))
+	if _, err := runtime.GetRunner().SudoCmd(backupCmd, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "backup etcd data dir failed")
+	}
+
+	restoreCmd := fmt.Sprintf("export ETCDCTL_API=3;"+
+		"%s/etcdctl snapshot restore %s --name=%s --initial-cluster=%s --initial-cluster-token=k8s_etcd "+
+		"--initial-advertise-peer-urls=https://%s:2380 --data-dir=%s",
+		common.BinDir, snapshotPath, etcdName, strings.Join(cluster.peerAddresses, ","), host.GetInternalAddress(), etcdDataDir)
+	if _, err := runtime.GetRunner().SudoCmd(restoreCmd, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restore etcd snapshot failed")
+	}
+	return nil
+}
+
+type StartETCD struct {
+	common.KubeAction
+}
+
+func (s *StartETCD) Execute(runtime connector.Runtime) error {
+	// Don't wait for the notification of etcd, a member can't be ready until the quorum of members is started.
+	if _, err := runtime.GetRunner().SudoCmd("systemctl daemon-reload && systemctl start --no-block etcd", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "start etcd failed")
+	}
+	return nil
+}
+
+// etcdctlV3 returns the etcdctl command using the v3 API and the admin certs of the host.
+func etcdctlV3(host connector.Host, endpoints string) string {
+	return fmt.Sprintf("export ETCDCTL_API=3;"+
+		"export ETCDCTL_CERT='/etc/ssl/etcd/ssl/admin-%s.pem';"+
+		"export ETCDCTL_KEY='/etc/ssl/etcd/ssl/admin-%s-key.pem';"+
+		"export ETCDCTL_CACERT='/etc/ssl/etcd/ssl/ca.pem';"+
+		"%s/etcdctl --endpoints=%s", host.GetName(), host.GetName(), common.BinDir, endpoints)
+}
+
+// etcdHostToDelete returns the etcd host which is going to be deleted.
+func etcdHostToDelete(runtime connector.Runtime, pipelineCache *cache.Cache) (connector.Host, error) {
+	nodeName, ok := pipelineCache.GetMustString("dstNode")
+	if !ok {
+		return nil, errors.New("get dstNode failed by pipeline cache")
+	}
+	for _, host := range runtime.GetHostsByRole(common.ETCD) {
+		if host.GetName() == nodeName {
+			return host, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not an etcd node", nodeName)
+}
+
+type CheckQuorum struct {
+	common.KubeAction
+}
+
+// Execute refuses to remove the member if the remaining members could not keep the quorum.
+func (c *CheckQuorum) Execute(runtime connector.Runtime) error {
+	dst, err := etcdHostToDelete(runtime, c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	if exist, ok := dst.GetCache().GetMustBool(common.ETCDExist); !ok || !exist {
+		return nil
+	}
+
+	var endpoints []string
+	for _, host := range runtime.GetHostsByRole(common.ETCD) {
+		if host.GetName() == dst.GetName() {
+			continue
+		}
+		if exist, ok := host.GetCache().GetMustBool(common.ETCDExist); ok && exist {
+			endpoints = append(endpoints, fmt.Sprintf("https://%s:2379", host.GetInternalAddress()))
+		}
+	}
+
+	// etcdctl exits with non-zero code if any endpoint is unhealthy, the healthy ones are counted from the output.
+	output, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("%s endpoint health 2>&1 || true", etcdctlV3(runtime.RemoteHost(), strings.Join(endpoints, ","))), true)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "check etcd endpoint health failed")
+	}
+	healthy := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, " is healthy") {
+			healthy++
+		}
+	}
+
+	quorum := len(endpoints)/2 + 1
+	if healthy < quorum {
+		return fmt.Errorf("removing etcd member %s would break the quorum: %d of the %d remaining members are healthy, at least %d are required",
+			dst.GetName(), healthy, len(endpoints), quorum)
+	}
+	return nil
+}
+
+type RemoveMember struct {
+	common.KubeAction
+}
+
+func (r *RemoveMember) Execute(runtime connector.Runtime) error {
+	dst, err := etcdHostToDelete(runtime, r.PipelineCache)
+	if err != nil {
+		return err
+	}
+
+	v, ok := r.PipelineCache.Get(common.ETCDCluster)
+	if !ok {
+		return errors.New("get etcd cluster status by pipeline cache failed")
+	}
+	cluster := v.(*EtcdCluster)
+
+	peerURL := fmt.Sprintf("https://%s:2380", dst.GetInternalAddress())
+	peerAddresses := make([]string, 0, len(cluster.peerAddresses))
+	for _, addr := range cluster.peerAddresses {
+		if !strings.HasSuffix(addr, "="+peerURL) {
+			peerAddresses = append(peerAddresses, addr)
+		}
+	}
+	cluster.peerAddresses = peerAddresses
+	r.PipelineCache.Set(common.ETCDCluster, cluster)
+
+	etcdctl := etcdctlV3(runtime.RemoteHost(), fmt.Sprintf("https://%s:2379", runtime.RemoteHost().GetInternalAddress()))
+	memberList, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s member list", etcdctl), true)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "list etcd member failed")
+	}
+
+	// e.g. 8e9e05c52164694d, started, etcd-node1, https://192.168.0.2:2380, https://192.168.0.2:2379, false
+	var memberID string
+	for _, line := range strings.Split(memberList, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) > 3 && strings.TrimSpace(fields[3]) == peerURL {
+			memberID = strings.TrimSpace(fields[0])
+			break
+		}
+	}
+	if memberID == "" {
+		logger.Log.Infof("%s is not a member of the etcd cluster, skip removing it", dst.GetName())
+		return nil
+	}
+
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s member remove %s", etcdctl, memberID), true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "remove etcd member failed")
+	}
+	return nil
+}
+
+type UninstallETCD struct {
+	common.KubeAction
+}
+
+// Execute stops the removed member and cleans its data, otherwise it would keep restarting with the stale data.
+func (u *UninstallETCD) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd("systemctl disable --now etcd", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "stop etcd failed")
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s /etc/etcd.env", etcdDataDir), true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "remove etcd data failed")
+	}
+	return nil
+}
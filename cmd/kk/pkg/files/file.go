@@ -49,6 +49,7 @@ const (
 	compose    = "compose"
 	containerd = "containerd"
 	runc       = "runc"
+	crio       = "crio"
 )
 
 // KubeBinary Type field const
@@ -62,6 +63,7 @@ const (
 	REGISTRY   = "registry"
 	CONTAINERD = "containerd"
 	RUNC       = "runc"
+	CRIO       = "crio"
 )
 
 type KubeBinary struct {
@@ -198,6 +200,13 @@ func NewKubeBinary(name, arch, version, prePath string, getCmd func(path, url st
 		if component.Zone == "cn" {
 			component.Url = fmt.Sprintf("https://kubernetes-release.pek3b.qingstor.com/opencontainers/runc/releases/download/%s/runc.%s", version, arch)
 		}
+	case crio:
+		component.Type = CRIO
+		component.FileName = fmt.Sprintf("cri-o.%s.v%s.tar.gz", arch, version)
+		component.Url = fmt.Sprintf("https://storage.googleapis.com/cri-o/artifacts/cri-o.%s.v%s.tar.gz", arch, version)
+		if component.Zone == "cn" {
+			component.Url = fmt.Sprintf("https://kubernetes-release.pek3b.qingstor.com/cri-o/artifacts/cri-o.%s.v%s.tar.gz", arch, version)
+		}
 	default:
 		logger.Log.Fatalf("unsupported kube binaries %s", name)
 	}
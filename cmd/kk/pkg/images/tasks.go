@@ -61,8 +61,11 @@ func (p *PullImage) Execute(runtime connector.Runtime) error {
 			GetImage(runtime, p.KubeConf, "flannel"),
 			GetImage(runtime, p.KubeConf, "kubeovn"),
 			GetImage(runtime, p.KubeConf, "haproxy"),
+			GetImage(runtime, p.KubeConf, "nginx"),
+			GetImage(runtime, p.KubeConf, "envoy"),
 			GetImage(runtime, p.KubeConf, "kubevip"),
 		}
+		i.Images = append(i.Images, StorageImages(runtime, p.KubeConf)...)
 
 		if err := i.PullImages(runtime, p.KubeConf); err != nil {
 			return err
@@ -71,6 +74,31 @@ func (p *PullImage) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// StorageImages returns the images of the storage providers, the disabled ones are filtered by PullImages.
+func StorageImages(runtime connector.ModuleRuntime, kubeConf *common.KubeConf) []Image {
+	names := []string{
+		"nfs-subdir-external-provisioner",
+		"local-path-provisioner",
+		"busybox",
+		"longhorn-manager",
+		"longhorn-engine",
+		"longhorn-instance-manager",
+		"longhorn-share-manager",
+		"backing-image-manager",
+		"longhorn-ui",
+		"longhorn-csi-attacher",
+		"longhorn-csi-provisioner",
+		"longhorn-csi-resizer",
+		"longhorn-csi-snapshotter",
+		"longhorn-csi-node-registrar",
+	}
+	images := make([]Image, 0, len(names))
+	for _, name := range names {
+		images = append(images, GetImage(runtime, kubeConf, name))
+	}
+	return images
+}
+
 // GetImage defines the list of all images and gets image object by name.
 func GetImage(runtime connector.ModuleRuntime, kubeConf *common.KubeConf, name string) Image {
 	var image Image
@@ -122,10 +150,26 @@ func GetImage(runtime connector.ModuleRuntime, kubeConf *common.KubeConf, name s
 		"kubeovn":                 {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "kubeovn", Repo: "kube-ovn", Tag: kubekeyv1alpha2.DefaultKubeovnVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "kubeovn")},
 		"multus":                  {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: kubekeyv1alpha2.DefaultKubeImageNamespace, Repo: "multus-cni", Tag: kubekeyv1alpha2.DefalutMultusVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.Contains(kubeConf.Cluster.Network.Plugin, "multus")},
 		// storage
-		"provisioner-localpv": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "openebs", Repo: "provisioner-localpv", Tag: "3.3.0", Group: kubekeyv1alpha2.Worker, Enable: false},
-		"linux-utils":         {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "openebs", Repo: "linux-utils", Tag: "3.3.0", Group: kubekeyv1alpha2.Worker, Enable: false},
+		"provisioner-localpv":             {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "openebs", Repo: "provisioner-localpv", Tag: "3.3.0", Group: kubekeyv1alpha2.Worker, Enable: false},
+		"linux-utils":                     {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "openebs", Repo: "linux-utils", Tag: "3.3.0", Group: kubekeyv1alpha2.Worker, Enable: false},
+		"nfs-subdir-external-provisioner": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: kubekeyv1alpha2.DefaultKubeImageNamespace, Repo: "nfs-subdir-external-provisioner", Tag: "v4.0.2", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableNFS()},
+		"local-path-provisioner":          {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "rancher", Repo: "local-path-provisioner", Tag: "v0.0.22", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLocalPath()},
+		"busybox":                         {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "library", Repo: "busybox", Tag: "1.31.1", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLocalPath()},
+		"longhorn-manager":                {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "longhorn-manager", Tag: "v1.2.4", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-engine":                 {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "longhorn-engine", Tag: "v1.2.4", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-instance-manager":       {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "longhorn-instance-manager", Tag: "v1_20220303", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-share-manager":          {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "longhorn-share-manager", Tag: "v1_20211020", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"backing-image-manager":           {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "backing-image-manager", Tag: "v2_20210820", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-ui":                     {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "longhorn-ui", Tag: "v1.2.4", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-csi-attacher":           {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "csi-attacher", Tag: "v3.2.1", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-csi-provisioner":        {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "csi-provisioner", Tag: "v2.1.2", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-csi-resizer":            {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "csi-resizer", Tag: "v1.2.0", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-csi-snapshotter":        {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "csi-snapshotter", Tag: "v3.0.3", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
+		"longhorn-csi-node-registrar":     {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "longhornio", Repo: "csi-node-driver-registrar", Tag: "v2.3.0", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Storage.EnableLonghorn()},
 		// load balancer
-		"haproxy": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "library", Repo: "haproxy", Tag: "2.3", Group: kubekeyv1alpha2.Worker, Enable: kubeConf.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		"haproxy": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "library", Repo: "haproxy", Tag: "2.3", Group: kubekeyv1alpha2.Worker, Enable: kubeConf.Cluster.ControlPlaneEndpoint.InternalLoadbalancer == kubekeyv1alpha2.Haproxy},
+		"nginx":   {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "library", Repo: "nginx", Tag: "1.21.6-alpine", Group: kubekeyv1alpha2.Worker, Enable: kubeConf.Cluster.ControlPlaneEndpoint.InternalLoadbalancer == kubekeyv1alpha2.Nginx},
+		"envoy":   {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "envoyproxy", Repo: "envoy", Tag: "v1.22.2", Group: kubekeyv1alpha2.Worker, Enable: kubeConf.Cluster.ControlPlaneEndpoint.InternalLoadbalancer == kubekeyv1alpha2.Envoy},
 		"kubevip": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "plndr", Repo: "kube-vip", Tag: "v0.5.0", Group: kubekeyv1alpha2.Master, Enable: kubeConf.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 		// kata-deploy
 		"kata-deploy": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: kubekeyv1alpha2.DefaultKubeImageNamespace, Repo: "kata-deploy", Tag: "stable", Group: kubekeyv1alpha2.Worker, Enable: kubeConf.Cluster.Kubernetes.EnableKataDeploy()},
@@ -158,6 +202,10 @@ func (s *SaveImages) Execute(runtime connector.Runtime) error {
 		if v, ok := auths[repo]; ok {
 			auth = v
 		}
+		// the images without a namespace, e.g. registry.k8s.io/pause:3.6, are saved into the library namespace
+		if len(imageFullName) == 2 {
+			imageFullName = []string{repo, "library", imageFullName[1]}
+		}
 
 		srcName := fmt.Sprintf("docker://%s", image)
 		for _, platform := range s.Manifest.Spec.Arches {
@@ -20,6 +20,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
 type NodeInCluster struct {
@@ -68,3 +69,38 @@ type UsePrivateRegstry struct {
 func (c *UsePrivateRegstry) PreCheck(_ connector.Runtime) (bool, error) {
 	return c.KubeConf.Cluster.Registry.PrivateRegistry != "", nil
 }
+
+type NodeNeedUpgrade struct {
+	common.KubePrepare
+	// Nodes are the nodes to check, it's the remote host if empty.
+	Nodes []string
+}
+
+// PreCheck returns true if one of the nodes is not running the desired version.
+func (n *NodeNeedUpgrade) PreCheck(runtime connector.Runtime) (bool, error) {
+	v, ok := n.PipelineCache.Get(common.ClusterStatus)
+	if !ok {
+		return false, errors.New("get k3s cluster status by pipeline cache failed")
+	}
+	cluster := v.(*K3sStatus)
+
+	nodes := n.Nodes
+	if len(nodes) == 0 {
+		nodes = []string{runtime.RemoteHost().GetName()}
+	}
+	desired := versionutil.MustParseGeneric(n.KubeConf.Cluster.Kubernetes.Version)
+	for _, node := range nodes {
+		nodeVersion, ok := cluster.NodesInfo[node]
+		if !ok || nodeVersion == "" {
+			continue
+		}
+		current, err := versionutil.ParseGeneric(nodeVersion)
+		if err != nil {
+			return false, errors.Wrapf(err, "parse the version %s of node %s failed", nodeVersion, node)
+		}
+		if !(current.AtLeast(desired) && desired.AtLeast(current)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -17,19 +17,29 @@
 package k8e
 
 import (
+	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/action"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/task"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/k8e/templates"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/kubernetes"
 )
 
 type StatusModule struct {
 	common.KubeModule
 }
 
+func (s *StatusModule) IsStateful() bool {
+	return true
+}
+
 func (s *StatusModule) Init() {
 	s.Name = "StatusModule"
 	s.Desc = "Get cluster status"
@@ -346,3 +356,148 @@ func (s *SaveKubeConfigModule) Init() {
 		save,
 	}
 }
+
+type UpgradePreCheckModule struct {
+	common.KubeModule
+}
+
+func (u *UpgradePreCheckModule) Init() {
+	u.Name = "K8eUpgradePreCheckModule"
+	u.Desc = "Do pre-check on k8e cluster before upgrading"
+
+	checkVersion := &task.RemoteTask{
+		Name:     "CheckUpgradeVersion",
+		Desc:     "Check the desired k8e version",
+		Hosts:    u.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(CheckUpgradeVersion),
+		Parallel: true,
+	}
+
+	ksVersionCheck := &task.RemoteTask{
+		Name:     "KsVersionCheck",
+		Desc:     "Check KubeSphere version",
+		Hosts:    u.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(precheck.KsVersionCheck),
+		Parallel: true,
+	}
+
+	getNodesStatus := &task.RemoteTask{
+		Name:     "GetKubernetesNodesStatus",
+		Desc:     "Get kubernetes nodes status",
+		Hosts:    u.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(precheck.GetKubernetesNodesStatus),
+		Parallel: true,
+	}
+
+	u.Tasks = []task.Interface{
+		checkVersion,
+		ksVersionCheck,
+		getNodesStatus,
+	}
+}
+
+// UpgradeModule upgrades the servers one node at a time and then the agents in batches of --max-unavailable.
+// The nodes of a batch are drained, their k8e binary is replaced and the service is restarted, then the next
+// batch is not touched until the nodes of this one are ready with the desired version.
+type UpgradeModule struct {
+	common.KubeModule
+}
+
+func (u *UpgradeModule) Init() {
+	u.Name = "K8eUpgradeModule"
+	u.Desc = "Upgrade k8e cluster"
+
+	masters := u.Runtime.GetHostsByRole(common.Master)
+	var agents []connector.Host
+	for _, h := range u.Runtime.GetHostsByRole(common.Worker) {
+		if !h.IsRole(common.Master) {
+			agents = append(agents, h)
+		}
+	}
+
+	u.Tasks = make([]task.Interface, 0, 5*(len(masters)+len(agents)))
+	for _, master := range masters {
+		u.Tasks = append(u.Tasks, u.upgradeTasks(masters[0], []connector.Host{master})...)
+	}
+	maxUnavailable := u.KubeConf.Arg.MaxUnavailable
+	if maxUnavailable <= 0 {
+		maxUnavailable = 1
+	}
+	for i := 0; i < len(agents); i += maxUnavailable {
+		end := i + maxUnavailable
+		if end > len(agents) {
+			end = len(agents)
+		}
+		u.Tasks = append(u.Tasks, u.upgradeTasks(masters[0], agents[i:end])...)
+	}
+}
+
+// upgradeTasks returns the tasks to upgrade a batch of nodes, the kubectl commands are executed on the given master.
+func (u *UpgradeModule) upgradeTasks(master connector.Host, batch []connector.Host) []task.Interface {
+	nodes := make([]string, 0, len(batch))
+	for _, h := range batch {
+		nodes = append(nodes, h.GetName())
+	}
+	desc := strings.Join(nodes, ", ")
+
+	drainTimeout := u.KubeConf.Arg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = kubernetes.DefaultDrainTimeout
+	}
+	drain := &task.RemoteTask{
+		Name:    "DrainNode",
+		Desc:    fmt.Sprintf("Drain node %s", desc),
+		Hosts:   []connector.Host{master},
+		Prepare: &NodeNeedUpgrade{Nodes: nodes},
+		Action:  &DrainNode{Nodes: nodes, Timeout: drainTimeout},
+		Retry:   2,
+	}
+
+	syncBinary := &task.RemoteTask{
+		Name:     "SyncKubeBinary",
+		Desc:     fmt.Sprintf("Synchronize k8e binaries to %s", desc),
+		Hosts:    batch,
+		Prepare:  new(NodeNeedUpgrade),
+		Action:   new(SyncKubeBinary),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	restart := &task.RemoteTask{
+		Name:     "RestartK8eService",
+		Desc:     fmt.Sprintf("Restart k8e service on %s", desc),
+		Hosts:    batch,
+		Prepare:  new(NodeNeedUpgrade),
+		Action:   new(RestartK8eService),
+		Parallel: true,
+	}
+
+	ready := &task.RemoteTask{
+		Name:    "CheckNodeReady",
+		Desc:    fmt.Sprintf("Wait for node %s to be ready", desc),
+		Hosts:   []connector.Host{master},
+		Prepare: &NodeNeedUpgrade{Nodes: nodes},
+		Action:  &CheckNodeReady{Nodes: nodes},
+		Retry:   60,
+		Delay:   5 * time.Second,
+	}
+
+	uncordon := &task.RemoteTask{
+		Name:    "UncordonNode",
+		Desc:    fmt.Sprintf("Uncordon node %s", desc),
+		Hosts:   []connector.Host{master},
+		Prepare: &NodeNeedUpgrade{Nodes: nodes},
+		Action:  &UncordonNode{Nodes: nodes},
+		Retry:   5,
+	}
+
+	var tasks []task.Interface
+	// the pods can not be moved anywhere in a single node cluster
+	if !u.KubeConf.Arg.SkipDrain && len(u.Runtime.GetHostsByRole(common.K8s)) > 1 {
+		tasks = append(tasks, drain)
+	}
+	return append(tasks, syncBinary, restart, ready, uncordon)
+}
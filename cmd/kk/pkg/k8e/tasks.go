@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
@@ -508,3 +509,117 @@ func (s *SaveKubeConfig) Execute(_ connector.Runtime) error {
 	}
 	return nil
 }
+
+type CheckUpgradeVersion struct {
+	common.KubeAction
+}
+
+func (c *CheckUpgradeVersion) Execute(_ connector.Runtime) error {
+	if exist, ok := c.PipelineCache.GetMustBool(common.ClusterExist); !ok || !exist {
+		return errors.New("the k8e cluster is not found, please create it before upgrading")
+	}
+	v, ok := c.PipelineCache.Get(common.ClusterStatus)
+	if !ok {
+		return errors.New("get k8e cluster status by pipeline cache failed")
+	}
+	cluster := v.(*K8eStatus)
+
+	current, err := versionutil.ParseGeneric(cluster.Version)
+	if err != nil {
+		return errors.Wrapf(err, "parse the current k8e version %s failed", cluster.Version)
+	}
+	desired, err := versionutil.ParseGeneric(c.KubeConf.Cluster.Kubernetes.Version)
+	if err != nil {
+		return errors.Wrapf(err, "parse the desired k8e version %s failed", c.KubeConf.Cluster.Kubernetes.Version)
+	}
+	if desired.LessThan(current) {
+		return errors.Errorf("downgrading k8e from %s to %s is not supported", cluster.Version, c.KubeConf.Cluster.Kubernetes.Version)
+	}
+	if desired.Major() != current.Major() || desired.Minor() > current.Minor()+1 {
+		return errors.Errorf("upgrading k8e from %s to %s skips minor versions, please upgrade one minor version at a time",
+			cluster.Version, c.KubeConf.Cluster.Kubernetes.Version)
+	}
+
+	c.PipelineCache.Set(common.K8sVersion, fmt.Sprintf("v%s", current.String()))
+	return nil
+}
+
+// DrainNode evicts the pods of the nodes through the eviction API, so the PodDisruptionBudgets are respected.
+type DrainNode struct {
+	common.KubeAction
+	Nodes   []string
+	Timeout time.Duration
+}
+
+func (d *DrainNode) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl drain %s --delete-emptydir-data --ignore-daemonsets --force --timeout=%s",
+		strings.Join(d.Nodes, " "), d.Timeout), true); err != nil {
+		return errors.Wrapf(err, "drain the node %s failed", strings.Join(d.Nodes, ", "))
+	}
+	return nil
+}
+
+type RestartK8eService struct {
+	common.KubeAction
+}
+
+func (r *RestartK8eService) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd("systemctl daemon-reload && systemctl restart k8e",
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart k8e failed")
+	}
+	return nil
+}
+
+type CheckNodeReady struct {
+	common.KubeAction
+	Nodes []string
+}
+
+func (c *CheckNodeReady) Execute(runtime connector.Runtime) error {
+	for _, node := range c.Nodes {
+		if err := c.checkNode(runtime, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CheckNodeReady) checkNode(runtime connector.Runtime, node string) error {
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("/usr/local/bin/kubectl get node %s --no-headers", node), false)
+	if err != nil {
+		return errors.Wrapf(err, "get the status of node %s failed", node)
+	}
+	// NAME STATUS ROLES AGE VERSION
+	fields := strings.Fields(output)
+	if len(fields) < 5 {
+		return errors.Errorf("unexpected status of node %s: %s", node, output)
+	}
+	if strings.Split(fields[1], ",")[0] != "Ready" {
+		return errors.Errorf("node %s is %s", node, fields[1])
+	}
+
+	current, err := versionutil.ParseGeneric(fields[4])
+	if err != nil {
+		return errors.Wrapf(err, "parse the version %s of node %s failed", fields[4], node)
+	}
+	desired := versionutil.MustParseGeneric(c.KubeConf.Cluster.Kubernetes.Version)
+	if !(current.AtLeast(desired) && desired.AtLeast(current)) {
+		return errors.Errorf("node %s is still running %s", node, fields[4])
+	}
+	return nil
+}
+
+type UncordonNode struct {
+	common.KubeAction
+	Nodes []string
+}
+
+func (u *UncordonNode) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl uncordon %s", strings.Join(u.Nodes, " ")), true); err != nil {
+		return errors.Wrapf(err, "uncordon the node %s failed", strings.Join(u.Nodes, ", "))
+	}
+	return nil
+}
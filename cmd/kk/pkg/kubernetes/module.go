@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/binaries"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/action"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/task"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/images"
@@ -36,6 +38,10 @@ type StatusModule struct {
 	common.KubeModule
 }
 
+func (k *StatusModule) IsStateful() bool {
+	return true
+}
+
 func (k *StatusModule) Init() {
 	k.Name = "KubernetesStatusModule"
 	k.Desc = "Get kubernetes cluster status"
@@ -127,6 +133,56 @@ func (i *InstallKubeBinariesModule) Init() {
 	}
 }
 
+// SyncPKIModule distributes the CAs provided by the user to the control-plane nodes before kubeadm signs the certificates.
+type SyncPKIModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (s *SyncPKIModule) IsSkip() bool {
+	return s.Skip
+}
+
+func (s *SyncPKIModule) Init() {
+	s.Name = "SyncPKIModule"
+	s.Desc = "Synchronize the provided CAs"
+
+	preparePKI := &task.LocalTask{
+		Name:   "PreparePKI",
+		Desc:   "Check the provided CAs and certs",
+		Action: new(PreparePKI),
+	}
+
+	checkPKI := &task.RemoteTask{
+		Name:  "CheckPKI",
+		Desc:  "Check the CAs of the control-plane in cluster",
+		Hosts: s.Runtime.GetHostsByRole(common.Master),
+		Prepare: &prepare.PrepareCollection{
+			new(NodeInCluster),
+		},
+		Action:   new(CheckPKI),
+		Parallel: true,
+	}
+
+	syncPKI := &task.RemoteTask{
+		Name:  "SyncPKI",
+		Desc:  "Synchronize the provided CAs and certs to control-plane",
+		Hosts: s.Runtime.GetHostsByRole(common.Master),
+		Prepare: &prepare.PrepareCollection{
+			&NodeInCluster{Not: true},
+		},
+		Action:   new(SyncPKI),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	s.Tasks = []task.Interface{
+		preparePKI,
+		checkPKI,
+		syncPKI,
+	}
+}
+
 type InitKubernetesModule struct {
 	common.KubeModule
 }
@@ -411,19 +467,22 @@ func (d *DeleteKubeNodeModule) Init() {
 	}
 }
 
+// SetUpgradePlanModule plans the versions to upgrade through from the current version to the desired version.
+// If the Limit is set and the desired version is at least Before, the plan stops at the Limit.
 type SetUpgradePlanModule struct {
 	common.KubeModule
-	Step UpgradeStep
+	Limit  string
+	Before string
 }
 
 func (s *SetUpgradePlanModule) Init() {
-	s.Name = fmt.Sprintf("SetUpgradePlanModule %d/%d", s.Step, len(UpgradeStepList))
+	s.Name = "SetUpgradePlanModule"
 	s.Desc = "Set upgrade plan"
 
 	plan := &task.LocalTask{
 		Name:   "SetUpgradePlan",
 		Desc:   "Set upgrade plan",
-		Action: &SetUpgradePlan{Step: s.Step},
+		Action: &SetUpgradePlan{Limit: s.Limit, Before: s.Before},
 	}
 
 	s.Tasks = []task.Interface{
@@ -431,14 +490,14 @@ func (s *SetUpgradePlanModule) Init() {
 	}
 }
 
+// ProgressiveUpgradeModule upgrades the cluster to the versions of the upgrade plan one after another.
 type ProgressiveUpgradeModule struct {
 	common.KubeModule
-	Step UpgradeStep
 }
 
 func (p *ProgressiveUpgradeModule) Init() {
-	p.Name = fmt.Sprintf("ProgressiveUpgradeModule %d/%d", p.Step, len(UpgradeStepList))
-	p.Desc = fmt.Sprintf("Progressive upgrade %d/%d", p.Step, len(UpgradeStepList))
+	p.Name = "ProgressiveUpgradeModule"
+	p.Desc = "Progressive upgrade"
 
 	nextVersion := &task.LocalTask{
 		Name:    "CalculateNextVersion",
@@ -473,15 +532,6 @@ func (p *ProgressiveUpgradeModule) Init() {
 		Retry:    2,
 	}
 
-	upgradeKubeMaster := &task.RemoteTask{
-		Name:     "UpgradeClusterOnMaster",
-		Desc:     "Upgrade cluster on master",
-		Hosts:    p.Runtime.GetHostsByRole(common.Master),
-		Prepare:  new(NotEqualPlanVersion),
-		Action:   &UpgradeKubeMaster{ModuleName: p.Name},
-		Parallel: false,
-	}
-
 	cluster := NewKubernetesStatus()
 	p.PipelineCache.GetOrSet(common.ClusterStatus, cluster)
 
@@ -494,18 +544,6 @@ func (p *ProgressiveUpgradeModule) Init() {
 		Parallel: false,
 	}
 
-	upgradeKubeWorker := &task.RemoteTask{
-		Name:  "UpgradeClusterOnWorker",
-		Desc:  "Upgrade cluster on worker",
-		Hosts: p.Runtime.GetHostsByRole(common.Worker),
-		Prepare: &prepare.PrepareCollection{
-			new(NotEqualPlanVersion),
-			new(common.OnlyWorker),
-		},
-		Action:   &UpgradeKubeWorker{ModuleName: p.Name},
-		Parallel: false,
-	}
-
 	reconfigureDNS := &task.RemoteTask{
 		Name:  "ReconfigureCoreDNS",
 		Desc:  "Reconfigure CoreDNS",
@@ -525,17 +563,112 @@ func (p *ProgressiveUpgradeModule) Init() {
 		Action:  new(SetCurrentK8sVersion),
 	}
 
+	masters := p.Runtime.GetHostsByRole(common.Master)
+	workers := make([]connector.Host, 0)
+	for _, h := range p.Runtime.GetHostsByRole(common.Worker) {
+		if !h.IsRole(common.Master) {
+			workers = append(workers, h)
+		}
+	}
+
 	p.Tasks = []task.Interface{
 		nextVersion,
 		download,
 		pull,
 		syncBinary,
-		upgradeKubeMaster,
-		clusterStatus,
-		upgradeKubeWorker,
-		reconfigureDNS,
-		currentVersion,
 	}
+	// the control plane is always upgraded one node at a time
+	for _, master := range masters {
+		p.Tasks = append(p.Tasks, p.rollingUpgradeTasks(masters[0], []connector.Host{master},
+			"UpgradeClusterOnMaster", &UpgradeKubeMaster{ModuleName: p.Name})...)
+	}
+	p.Tasks = append(p.Tasks, clusterStatus)
+	maxUnavailable := p.KubeConf.Arg.MaxUnavailable
+	if maxUnavailable <= 0 {
+		maxUnavailable = 1
+	}
+	for i := 0; i < len(workers); i += maxUnavailable {
+		end := i + maxUnavailable
+		if end > len(workers) {
+			end = len(workers)
+		}
+		p.Tasks = append(p.Tasks, p.rollingUpgradeTasks(masters[0], workers[i:end],
+			"UpgradeClusterOnWorker", &UpgradeKubeWorker{ModuleName: p.Name})...)
+	}
+	p.Tasks = append(p.Tasks, reconfigureDNS, currentVersion)
+}
+
+// rollingUpgradeTasks returns the tasks to upgrade a batch of nodes: cordon -> drain -> upgrade -> uncordon ->
+// wait for the nodes and the kube-system pods on them to be healthy. The kubectl commands are executed on the
+// given master. The module fails and stops at the first batch which does not become healthy.
+func (p *ProgressiveUpgradeModule) rollingUpgradeTasks(master connector.Host, batch []connector.Host,
+	name string, upgrade action.Action) []task.Interface {
+	nodes := make([]string, 0, len(batch))
+	for _, h := range batch {
+		nodes = append(nodes, h.GetName())
+	}
+	desc := strings.Join(nodes, ", ")
+
+	cordon := &task.RemoteTask{
+		Name:     "CordonNode",
+		Desc:     fmt.Sprintf("Cordon node %s", desc),
+		Hosts:    []connector.Host{master},
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   &CordonNodes{Nodes: nodes},
+		Parallel: false,
+		Retry:    3,
+	}
+
+	drainTimeout := p.KubeConf.Arg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+	drain := &task.RemoteTask{
+		Name:     "DrainNode",
+		Desc:     fmt.Sprintf("Drain node %s", desc),
+		Hosts:    []connector.Host{master},
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   &DrainNodes{Nodes: nodes, Timeout: drainTimeout},
+		Parallel: false,
+	}
+
+	upgradeNode := &task.RemoteTask{
+		Name:     name,
+		Desc:     fmt.Sprintf("Upgrade cluster on %s", desc),
+		Hosts:    batch,
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   upgrade,
+		Parallel: true,
+	}
+
+	uncordon := &task.RemoteTask{
+		Name:     "UncordonNode",
+		Desc:     fmt.Sprintf("Uncordon node %s", desc),
+		Hosts:    []connector.Host{master},
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   &UncordonNodes{Nodes: nodes},
+		Parallel: false,
+		Retry:    5,
+		Delay:    10 * time.Second,
+	}
+
+	healthCheck := &task.RemoteTask{
+		Name:     "CheckNodeHealth",
+		Desc:     fmt.Sprintf("Wait for node %s and its kube-system pods to be ready", desc),
+		Hosts:    []connector.Host{master},
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   &CheckNodesHealth{Nodes: nodes},
+		Parallel: false,
+		Retry:    30,
+		Delay:    10 * time.Second,
+	}
+
+	tasks := []task.Interface{cordon}
+	// the pods can not be moved anywhere in a single node cluster
+	if !p.KubeConf.Arg.SkipDrain && len(p.Runtime.GetHostsByRole(common.K8s)) > 1 {
+		tasks = append(tasks, drain)
+	}
+	return append(tasks, upgradeNode, uncordon, healthCheck)
 }
 
 func (p *ProgressiveUpgradeModule) Until() (*bool, error) {
@@ -0,0 +1,261 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	certutil "k8s.io/client-go/util/cert"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils/certs"
+)
+
+// externalCACerts are the files of a control-plane node required by kubeadm in the external CA mode,
+// relative to /etc/kubernetes. The ones of the front proxy are only required if its CA key is not provided either.
+// kubelet.conf is only required by the master which initializes the cluster, kubeadm join refuses an existing one
+// and the kubelets of the other masters get their certificates by TLS bootstrapping.
+var (
+	externalCACerts = []string{
+		"pki/apiserver.crt",
+		"pki/apiserver.key",
+		"pki/apiserver-kubelet-client.crt",
+		"pki/apiserver-kubelet-client.key",
+		"pki/sa.key",
+		"pki/sa.pub",
+		"admin.conf",
+		"controller-manager.conf",
+		"scheduler.conf",
+	}
+	externalCAInitCerts = []string{
+		"kubelet.conf",
+	}
+	externalFrontProxyCACerts = []string{
+		"pki/front-proxy-client.crt",
+		"pki/front-proxy-client.key",
+	}
+)
+
+func localPKIDir(runtime connector.Runtime) string {
+	return filepath.Join(runtime.GetWorkDir(), "pki", "kubernetes")
+}
+
+// PreparePKI checks the CAs provided by the user, and the certificates issued by them in the external CA mode,
+// then copies the CAs into the work dir.
+type PreparePKI struct {
+	common.KubeAction
+}
+
+func (p *PreparePKI) Execute(runtime connector.Runtime) error {
+	pki := p.KubeConf.Cluster.Kubernetes.PKI
+	dir := localPKIDir(runtime)
+	if err := util.CreateDir(dir); err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", dir)
+	}
+
+	cas := map[string]*kubekeyapiv1alpha2.CertificateAuthority{
+		"ca":             pki.CA,
+		"front-proxy-ca": pki.FrontProxyCA,
+	}
+	for name, ca := range cas {
+		if ca == nil {
+			continue
+		}
+		caCert, certPEM, keyPEM, err := certs.ReadCertificateAuthorityFiles(ca.CertFile, ca.KeyFile)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s.crt", name)
+		}
+		keyPath := filepath.Join(dir, name+".key")
+		if keyPEM == nil {
+			_ = os.Remove(keyPath)
+			if err := checkExternalCACerts(runtime, pki.CertsDir, name, caCert); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write %s.key", name)
+		}
+	}
+	return nil
+}
+
+// checkExternalCACerts checks that the certificates issued by the CA are provided for all the control-plane nodes.
+func checkExternalCACerts(runtime connector.Runtime, certsDir, caName string, ca *x509.Certificate) error {
+	if certsDir == "" {
+		return errors.Errorf("kubernetes.pki.certsDir is required as the key of the %s is not provided", caName)
+	}
+	for i, host := range runtime.GetHostsByRole(common.Master) {
+		var files []string
+		switch {
+		case caName == "front-proxy-ca":
+			files = externalFrontProxyCACerts
+		case i == 0:
+			files = append(append(files, externalCACerts...), externalCAInitCerts...)
+		default:
+			files = externalCACerts
+		}
+		for _, file := range files {
+			path := filepath.Join(certsDir, host.GetName(), file)
+			if !util.IsExist(path) {
+				return errors.Errorf("%s is required as the key of the %s is not provided", path, caName)
+			}
+			if filepath.Ext(path) != ".crt" {
+				continue
+			}
+			leaves, err := certutil.CertsFromFile(path)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse %s", path)
+			}
+			if err := leaves[0].CheckSignatureFrom(ca); err != nil {
+				return errors.Wrapf(err, "%s is not issued by the %s", path, caName)
+			}
+		}
+	}
+	return nil
+}
+
+// SyncPKI copies the CAs provided by the user, and the certificates issued by them in the external CA mode,
+// to the control-plane node before kubeadm runs, so that kubeadm uses them instead of signing new ones.
+type SyncPKI struct {
+	common.KubeAction
+}
+
+func (s *SyncPKI) Execute(runtime connector.Runtime) error {
+	exist, _ := s.PipelineCache.GetMustBool(common.ClusterExist)
+	initMaster := !exist && runtime.RemoteHost().GetName() == runtime.GetHostsByRole(common.Master)[0].GetName()
+	return SyncPKIFiles(runtime, s.KubeConf, initMaster)
+}
+
+// SyncPKIFiles copies the PKI files to the control-plane node, it's also used to restore them after kubeadm reset.
+// initMaster is true if the node initializes the cluster rather than joins it.
+func SyncPKIFiles(runtime connector.Runtime, kubeConf *common.KubeConf, initMaster bool) error {
+	pki := kubeConf.Cluster.Kubernetes.PKI
+	host := runtime.RemoteHost()
+	dir := localPKIDir(runtime)
+
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("mkdir -p %s", common.KubeCertDir), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "create dir %s failed", common.KubeCertDir)
+	}
+
+	for name, ca := range map[string]*kubekeyapiv1alpha2.CertificateAuthority{"ca": pki.CA, "front-proxy-ca": pki.FrontProxyCA} {
+		if ca == nil {
+			continue
+		}
+		local := filepath.Join(dir, name+".crt")
+		remote := filepath.Join(common.KubeCertDir, name+".crt")
+		if err := checkRemoteCA(runtime, local, remote); err != nil {
+			return err
+		}
+		if err := runtime.GetRunner().SudoScp(local, remote); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "sync %s failed", remote)
+		}
+		if ca.KeyFile == "" {
+			continue
+		}
+		key := filepath.Join(common.KubeCertDir, name+".key")
+		if err := runtime.GetRunner().SudoScp(filepath.Join(dir, name+".key"), key); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "sync %s failed", key)
+		}
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("chmod 600 %s", key), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "chmod %s failed", key)
+		}
+	}
+
+	if !pki.ExternalCA() {
+		return nil
+	}
+	var files []string
+	if pki.CA != nil && pki.CA.KeyFile == "" {
+		files = append(files, externalCACerts...)
+		if initMaster {
+			files = append(files, externalCAInitCerts...)
+		}
+	}
+	if pki.FrontProxyCA != nil && pki.FrontProxyCA.KeyFile == "" {
+		files = append(files, externalFrontProxyCACerts...)
+	}
+	for _, file := range files {
+		local := filepath.Join(pki.CertsDir, host.GetName(), file)
+		if err := runtime.GetRunner().SudoScp(local, filepath.Join(common.KubeConfigDir, file)); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "sync %s failed", file)
+		}
+	}
+	if _, err := runtime.GetRunner().SudoCmd("chmod 600 /etc/kubernetes/pki/*.key /etc/kubernetes/*.conf", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "chmod the certificates failed")
+	}
+	return nil
+}
+
+// CheckPKI checks that the CAs of the control-plane node in the cluster are the provided ones.
+type CheckPKI struct {
+	common.KubeAction
+}
+
+func (c *CheckPKI) Execute(runtime connector.Runtime) error {
+	pki := c.KubeConf.Cluster.Kubernetes.PKI
+	for name, ca := range map[string]*kubekeyapiv1alpha2.CertificateAuthority{"ca": pki.CA, "front-proxy-ca": pki.FrontProxyCA} {
+		if ca == nil {
+			continue
+		}
+		if err := checkRemoteCA(runtime, filepath.Join(localPKIDir(runtime), name+".crt"), filepath.Join(common.KubeCertDir, name+".crt")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRemoteCA refuses to replace a different CA on the node, the CA of a running cluster is never replaced by an external one.
+func checkRemoteCA(runtime connector.Runtime, local, remote string) error {
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("cat %s 2>/dev/null || true", remote), false)
+	if err != nil {
+		return errors.Wrapf(errors.WithStack(err), "read %s failed", remote)
+	}
+	if strings.TrimSpace(output) == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(local)
+	if err != nil {
+		return errors.Wrapf(err, "read %s failed", local)
+	}
+	inUse, err := certutil.ParseCertsPEM([]byte(output))
+	if err != nil {
+		return errors.Wrapf(err, "parse %s failed", remote)
+	}
+	provided, err := certutil.ParseCertsPEM(data)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s failed", local)
+	}
+	if !bytes.Equal(inUse[0].Raw, provided[0].Raw) {
+		return errors.Errorf("%s of %s is different from the provided one, the CA of a running cluster can not be replaced, set kubernetes.pki to the CA in use",
+			remote, runtime.RemoteHost().GetName())
+	}
+	return nil
+}
@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -51,6 +50,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/plugins/dns"
 	dnsTemplates "github.com/kubesphere/kubekey/cmd/kk/pkg/plugins/dns/templates"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/utils"
+	versionk8s "github.com/kubesphere/kubekey/cmd/kk/pkg/version/kubernetes"
 )
 
 type GetClusterStatus struct {
@@ -334,6 +334,12 @@ func (k *KubeadmInit) Execute(runtime connector.Runtime) error {
 			resetCmd = resetCmd + " --cri-socket " + k.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
 		}
 		_, _ = runtime.GetRunner().SudoCmd(resetCmd, true)
+		// kubeadm reset removes the certificates provided by the user
+		if k.KubeConf.Cluster.Kubernetes.PKI.Enabled() {
+			if syncErr := SyncPKIFiles(runtime, k.KubeConf, true); syncErr != nil {
+				return errors.Wrapf(syncErr, "init kubernetes cluster failed: %v", err)
+			}
+		}
 		return errors.Wrap(errors.WithStack(err), "init kubernetes cluster failed")
 	}
 	return nil
@@ -421,6 +427,12 @@ func (j *JoinNode) Execute(runtime connector.Runtime) error {
 			resetCmd = resetCmd + " --cri-socket " + j.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
 		}
 		_, _ = runtime.GetRunner().SudoCmd(resetCmd, true)
+		// kubeadm reset removes the certificates provided by the user
+		if j.KubeConf.Cluster.Kubernetes.PKI.Enabled() && runtime.RemoteHost().IsRole(common.Master) {
+			if syncErr := SyncPKIFiles(runtime, j.KubeConf, false); syncErr != nil {
+				return errors.Wrapf(syncErr, "join node failed: %v", err)
+			}
+		}
 		return errors.Wrap(errors.WithStack(err), "join node failed")
 	}
 	return nil
@@ -545,6 +557,109 @@ func (d *DrainNode) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// DefaultDrainTimeout is the time to wait for the pods of a node to be evicted during the rolling upgrade.
+const DefaultDrainTimeout = 5 * time.Minute
+
+type CordonNodes struct {
+	common.KubeAction
+	Nodes []string
+}
+
+func (c *CordonNodes) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl cordon %s", strings.Join(c.Nodes, " ")), true); err != nil {
+		return errors.Wrapf(err, "cordon the node %s failed", strings.Join(c.Nodes, ", "))
+	}
+	return nil
+}
+
+// DrainNodes evicts the pods through the eviction API, so the PodDisruptionBudgets are respected.
+// It fails if the pods can not be evicted in time.
+type DrainNodes struct {
+	common.KubeAction
+	Nodes   []string
+	Timeout time.Duration
+}
+
+func (d *DrainNodes) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl drain %s --delete-emptydir-data --ignore-daemonsets --force --timeout=%s",
+		strings.Join(d.Nodes, " "), d.Timeout), true); err != nil {
+		return errors.Wrapf(err, "drain the node %s failed", strings.Join(d.Nodes, ", "))
+	}
+	return nil
+}
+
+type UncordonNodes struct {
+	common.KubeAction
+	Nodes []string
+}
+
+func (u *UncordonNodes) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl uncordon %s", strings.Join(u.Nodes, " ")), true); err != nil {
+		return errors.Wrapf(err, "uncordon the node %s failed", strings.Join(u.Nodes, ", "))
+	}
+	return nil
+}
+
+// CheckNodesHealth checks that the nodes are ready with the upgraded kubelet, and that the kube-system pods
+// running on them are ready.
+type CheckNodesHealth struct {
+	common.KubeAction
+	Nodes []string
+}
+
+func (c *CheckNodesHealth) Execute(runtime connector.Runtime) error {
+	for _, node := range c.Nodes {
+		output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("/usr/local/bin/kubectl get node %s --no-headers", node), false)
+		if err != nil {
+			return errors.Wrapf(err, "get the status of node %s failed", node)
+		}
+		// NAME STATUS ROLES AGE VERSION
+		fields := strings.Fields(output)
+		if len(fields) < 5 {
+			return errors.Errorf("unexpected status of node %s: %s", node, output)
+		}
+		if strings.Split(fields[1], ",")[0] != "Ready" {
+			return errors.Errorf("node %s is %s", node, fields[1])
+		}
+		if fields[4] != c.KubeConf.Cluster.Kubernetes.Version {
+			return errors.Errorf("node %s is running kubelet %s, but %s is expected", node, fields[4], c.KubeConf.Cluster.Kubernetes.Version)
+		}
+
+		pods, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+			"/usr/local/bin/kubectl get pods -n kube-system --no-headers --field-selector spec.nodeName=%s", node), false)
+		if err != nil {
+			return errors.Wrapf(err, "get the kube-system pods on node %s failed", node)
+		}
+		if notReady := notReadyPods(pods); len(notReady) > 0 {
+			return errors.Errorf("the kube-system pods %s on node %s are not ready", strings.Join(notReady, ", "), node)
+		}
+	}
+	return nil
+}
+
+// notReadyPods parses the output of "kubectl get pods --no-headers" and returns the pods which are not ready.
+func notReadyPods(output string) []string {
+	notReady := make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		// NAME READY STATUS RESTARTS AGE
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[2] == "Completed" || fields[2] == "Succeeded" {
+			continue
+		}
+		ready := strings.Split(fields[1], "/")
+		if fields[2] != "Running" || len(ready) != 2 || ready[0] != ready[1] {
+			notReady = append(notReady, fields[0])
+		}
+	}
+	return notReady
+}
+
 type KubectlDeleteNode struct {
 	common.KubeAction
 }
@@ -564,10 +679,11 @@ func (k *KubectlDeleteNode) Execute(runtime connector.Runtime) error {
 
 type SetUpgradePlan struct {
 	common.KubeAction
-	Step UpgradeStep
+	Limit  string
+	Before string
 }
 
-func (s *SetUpgradePlan) Execute(_ connector.Runtime) error {
+func (s *SetUpgradePlan) Execute(runtime connector.Runtime) error {
 	currentVersion, ok := s.PipelineCache.GetMustString(common.K8sVersion)
 	if !ok {
 		return errors.New("get current Kubernetes version failed by pipeline cache")
@@ -587,67 +703,71 @@ func (s *SetUpgradePlan) Execute(_ connector.Runtime) error {
 		os.Exit(0)
 	}
 
-	if s.Step == ToV121 {
-		v122 := versionutil.MustParseSemantic("v1.22.0")
-		atLeast := versionutil.MustParseSemantic(desiredVersion).AtLeast(v122)
-		cmp, err := versionutil.MustParseSemantic(currentVersion).Compare("v1.21.5")
-		if err != nil {
-			return err
-		}
-		if atLeast && cmp <= 0 {
-			desiredVersion = "v1.21.5"
+	if s.Limit != "" && s.Before != "" {
+		limit := versionutil.MustParseSemantic(s.Limit)
+		atLeast := versionutil.MustParseSemantic(desiredVersion).AtLeast(versionutil.MustParseSemantic(s.Before))
+		if atLeast && !limit.LessThan(versionutil.MustParseSemantic(currentVersion)) {
+			desiredVersion = s.Limit
 		}
 	}
 
+	path, err := versionk8s.UpgradePath(currentVersion, desiredVersion)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUpgradePath(runtime, path); err != nil {
+		return err
+	}
+	if len(path) > 0 {
+		logger.Log.Messagef(common.LocalHost, "upgrade path: %s -> %s", currentVersion, strings.Join(path, " -> "))
+	}
+
+	s.PipelineCache.Set(common.UpgradePath, path)
 	s.PipelineCache.Set(common.PlanK8sVersion, desiredVersion)
 	return nil
 }
 
+// checkUpgradePath makes sure that the binaries of every version in the path can be verified,
+// and that they are provided by the artifact in an offline environment.
+func (s *SetUpgradePlan) checkUpgradePath(runtime connector.Runtime, path []string) error {
+	archs := make(map[string]bool)
+	for _, host := range s.KubeConf.Cluster.Hosts {
+		archs[host.Arch] = true
+	}
+	for _, version := range path {
+		for arch := range archs {
+			for _, name := range []string{"kubeadm", "kubelet", "kubectl"} {
+				binary := files.NewKubeBinary(name, arch, version, runtime.GetWorkDir(), s.KubeConf.Arg.DownloadCommand)
+				if binary.GetSha256() == "" {
+					return errors.Errorf("the %s %s binary of Kubernetes %s in the upgrade path is unknown", arch, name, version)
+				}
+				if s.KubeConf.Arg.Artifact != "" && !util.IsExist(binary.Path()) {
+					return errors.Errorf("the artifact does not contain the %s %s binary of Kubernetes %s in the upgrade path, "+
+						"please add the version to the manifest and export the artifact again", arch, name, version)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 type CalculateNextVersion struct {
 	common.KubeAction
 }
 
 func (c *CalculateNextVersion) Execute(_ connector.Runtime) error {
-	currentVersion, ok := c.PipelineCache.GetMustString(common.K8sVersion)
+	v, ok := c.PipelineCache.Get(common.UpgradePath)
 	if !ok {
-		return errors.New("get current Kubernetes version failed by pipeline cache")
+		return errors.New("get upgrade path failed by pipeline cache")
 	}
-	planVersion, ok := c.PipelineCache.GetMustString(common.PlanK8sVersion)
-	if !ok {
-		return errors.New("get upgrade plan Kubernetes version failed by pipeline cache")
-	}
-	nextVersionStr := calculateNextStr(currentVersion, planVersion)
-	c.KubeConf.Cluster.Kubernetes.Version = nextVersionStr
-	return nil
-}
-
-func calculateNextStr(currentVersion, desiredVersion string) string {
-	current := versionutil.MustParseSemantic(currentVersion)
-	target := versionutil.MustParseSemantic(desiredVersion)
-	var nextVersionMinor uint
-	if target.Minor() == current.Minor() {
-		nextVersionMinor = current.Minor()
-	} else {
-		nextVersionMinor = current.Minor() + 1
+	path := v.([]string)
+	if len(path) == 0 {
+		return errors.New("no version left in the upgrade path")
 	}
 
-	if nextVersionMinor == target.Minor() {
-		return desiredVersion
-	} else {
-		nextVersionPatchList := make([]int, 0)
-		for supportVersionStr := range files.FileSha256["kubeadm"]["amd64"] {
-			supportVersion := versionutil.MustParseSemantic(supportVersionStr)
-			if supportVersion.Minor() == nextVersionMinor {
-				nextVersionPatchList = append(nextVersionPatchList, int(supportVersion.Patch()))
-			}
-		}
-		sort.Ints(nextVersionPatchList)
-
-		nextVersion := current.WithMinor(nextVersionMinor)
-		nextVersion = nextVersion.WithPatch(uint(nextVersionPatchList[len(nextVersionPatchList)-1]))
-
-		return fmt.Sprintf("v%s", nextVersion.String())
-	}
+	c.KubeConf.Cluster.Kubernetes.Version = path[0]
+	c.PipelineCache.Set(common.UpgradePath, path[1:])
+	return nil
 }
 
 type UpgradeKubeMaster struct {
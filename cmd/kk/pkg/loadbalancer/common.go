@@ -18,4 +18,10 @@ package loadbalancer
 
 const (
 	LocalServer = "server: https://127.0.0.1"
+
+	K3sPodManifestDir = "/var/lib/rancher/k3s/agent/pod-manifests"
+
+	HaproxyServiceConfig      = "/etc/haproxy/haproxy.cfg"
+	KeepalivedConfigDir       = "/etc/keepalived"
+	DefaultKeepalivedPriority = 100
 )
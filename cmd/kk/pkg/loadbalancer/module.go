@@ -17,11 +17,12 @@
 package loadbalancer
 
 import (
-	"path/filepath"
+	"fmt"
 
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/action"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/cache"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/task"
@@ -29,33 +30,31 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/loadbalancer/templates"
 )
 
-type HaproxyModule struct {
+// InternalLoadbalancerModule runs haproxy, nginx or envoy on each worker as the internal load balancer of the apiservers.
+type InternalLoadbalancerModule struct {
 	common.KubeModule
 	Skip bool
 }
 
-func (h *HaproxyModule) IsSkip() bool {
+func (h *InternalLoadbalancerModule) IsSkip() bool {
 	return h.Skip
 }
 
-func (h *HaproxyModule) Init() {
+func (h *InternalLoadbalancerModule) Init() {
 	h.Name = "InternalLoadbalancerModule"
 	h.Desc = "Install internal load balancer"
 
-	haproxyCfg := &task.RemoteTask{
-		Name:    "GenerateHaproxyConfig",
-		Desc:    "Generate haproxy.cfg",
+	proxy := getLocalProxy(h.KubeConf)
+
+	proxyCfg := &task.RemoteTask{
+		Name:    "GenerateLoadbalancerConfig",
+		Desc:    fmt.Sprintf("Generate %s", proxy.Config.Name()),
 		Hosts:   h.Runtime.GetHostsByRole(common.Worker),
 		Prepare: new(common.OnlyWorker),
 		Action: &action.Template{
-			Template: templates.HaproxyConfig,
-			Dst:      filepath.Join(common.HaproxyDir, templates.HaproxyConfig.Name()),
-			Data: util.Data{
-				"MasterNodes":                          templates.MasterNodeStr(h.Runtime, h.KubeConf),
-				"LoadbalancerApiserverPort":            kubekeyapiv1alpha2.DefaultApiserverPort,
-				"LoadbalancerApiserverHealthcheckPort": 8081,
-				"KubernetesType":                       h.KubeConf.Cluster.Kubernetes.Type,
-			},
+			Template: proxy.Config,
+			Dst:      proxy.configPath(),
+			Data:     proxy.configData(h.Runtime, h.KubeConf, kubekeyapiv1alpha2.DefaultApiserverPort),
 		},
 		Parallel: true,
 	}
@@ -64,22 +63,22 @@ func (h *HaproxyModule) Init() {
 	// It will make load balancer reload when config changes.
 	getMd5Sum := &task.RemoteTask{
 		Name:     "GetChecksumFromConfig",
-		Desc:     "Calculate the MD5 value according to haproxy.cfg",
+		Desc:     fmt.Sprintf("Calculate the MD5 value according to %s", proxy.Config.Name()),
 		Hosts:    h.Runtime.GetHostsByRole(common.Worker),
 		Prepare:  new(common.OnlyWorker),
 		Action:   new(GetChecksum),
 		Parallel: true,
 	}
 
-	haproxyManifestK8s := &task.RemoteTask{
-		Name:  "GenerateHaproxyManifest",
-		Desc:  "Generate haproxy manifest",
+	proxyManifestK8s := &task.RemoteTask{
+		Name:  "GenerateLoadbalancerManifest",
+		Desc:  fmt.Sprintf("Generate %s manifest", proxy.Image),
 		Hosts: h.Runtime.GetHostsByRole(common.Worker),
 		Prepare: &prepare.PrepareCollection{
 			new(common.OnlyWorker),
 			new(common.OnlyKubernetes),
 		},
-		Action:   new(GenerateHaproxyManifest),
+		Action:   &GenerateLoadbalancerManifest{ManifestDir: common.KubeManifestDir},
 		Parallel: true,
 	}
 
@@ -128,9 +127,9 @@ func (h *HaproxyModule) Init() {
 	}
 
 	h.Tasks = []task.Interface{
-		haproxyCfg,
+		proxyCfg,
 		getMd5Sum,
-		haproxyManifestK8s,
+		proxyManifestK8s,
 		updateKubeletConfig,
 		updateKubeProxyConfig,
 		updateHostsFile,
@@ -200,33 +199,31 @@ func (k *KubevipModule) Init() {
 	}
 }
 
-type K3sHaproxyModule struct {
+// K3sInternalLoadbalancerModule runs haproxy, nginx or envoy on each k3s agent as the internal load balancer of the apiservers.
+type K3sInternalLoadbalancerModule struct {
 	common.KubeModule
 	Skip bool
 }
 
-func (k *K3sHaproxyModule) IsSkip() bool {
+func (k *K3sInternalLoadbalancerModule) IsSkip() bool {
 	return k.Skip
 }
 
-func (k *K3sHaproxyModule) Init() {
+func (k *K3sInternalLoadbalancerModule) Init() {
 	k.Name = "InternalLoadbalancerModule"
-	k.Name = "Install internal load balancer"
+	k.Desc = "Install internal load balancer"
 
-	haproxyCfg := &task.RemoteTask{
-		Name:    "GenerateHaproxyConfig",
-		Desc:    "Generate haproxy.cfg",
+	proxy := getLocalProxy(k.KubeConf)
+
+	proxyCfg := &task.RemoteTask{
+		Name:    "GenerateLoadbalancerConfig",
+		Desc:    fmt.Sprintf("Generate %s", proxy.Config.Name()),
 		Hosts:   k.Runtime.GetHostsByRole(common.Worker),
 		Prepare: new(common.OnlyWorker),
 		Action: &action.Template{
-			Template: templates.HaproxyConfig,
-			Dst:      filepath.Join(common.HaproxyDir, templates.HaproxyConfig.Name()),
-			Data: util.Data{
-				"MasterNodes":                          templates.MasterNodeStr(k.Runtime, k.KubeConf),
-				"LoadbalancerApiserverPort":            k.KubeConf.Cluster.ControlPlaneEndpoint.Port,
-				"LoadbalancerApiserverHealthcheckPort": 8081,
-				"KubernetesType":                       k.KubeConf.Cluster.Kubernetes.Type,
-			},
+			Template: proxy.Config,
+			Dst:      proxy.configPath(),
+			Data:     proxy.configData(k.Runtime, k.KubeConf, k.KubeConf.Cluster.ControlPlaneEndpoint.Port),
 		},
 		Parallel: true,
 	}
@@ -235,22 +232,22 @@ func (k *K3sHaproxyModule) Init() {
 	// It will make load balancer reload when config changes.
 	getMd5Sum := &task.RemoteTask{
 		Name:     "GetChecksumFromConfig",
-		Desc:     "Calculate the MD5 value according to haproxy.cfg",
+		Desc:     fmt.Sprintf("Calculate the MD5 value according to %s", proxy.Config.Name()),
 		Hosts:    k.Runtime.GetHostsByRole(common.Worker),
 		Prepare:  new(common.OnlyWorker),
 		Action:   new(GetChecksum),
 		Parallel: true,
 	}
 
-	haproxyManifestK3s := &task.RemoteTask{
-		Name:  "GenerateHaproxyManifestK3s",
-		Desc:  "Generate haproxy manifest",
+	proxyManifestK3s := &task.RemoteTask{
+		Name:  "GenerateLoadbalancerManifestK3s",
+		Desc:  fmt.Sprintf("Generate %s manifest", proxy.Image),
 		Hosts: k.Runtime.GetHostsByRole(common.Worker),
 		Prepare: &prepare.PrepareCollection{
 			new(common.OnlyWorker),
 			new(common.OnlyK3s),
 		},
-		Action:   new(GenerateK3sHaproxyManifest),
+		Action:   &GenerateLoadbalancerManifest{ManifestDir: K3sPodManifestDir},
 		Parallel: true,
 	}
 
@@ -279,9 +276,9 @@ func (k *K3sHaproxyModule) Init() {
 	}
 
 	k.Tasks = []task.Interface{
-		haproxyCfg,
+		proxyCfg,
 		getMd5Sum,
-		haproxyManifestK3s,
+		proxyManifestK3s,
 		updateK3sConfig,
 		updateHostsFile,
 	}
@@ -344,6 +341,122 @@ func (k *K3sKubevipModule) Init() {
 	}
 }
 
+// ExternalLoadbalancerModule runs haproxy and keepalived as systemd services on the loadbalancer nodes,
+// the VIP held by keepalived is the address of the control plane endpoint.
+// It's executed again when the control plane nodes are added or deleted to regenerate the backends of haproxy.
+type ExternalLoadbalancerModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (e *ExternalLoadbalancerModule) IsSkip() bool {
+	return e.Skip
+}
+
+func (e *ExternalLoadbalancerModule) Init() {
+	e.Name = "ExternalLoadbalancerModule"
+	e.Desc = "Install haproxy and keepalived on the loadbalancer nodes"
+
+	install := &task.RemoteTask{
+		Name:     "InstallHaproxyAndKeepalived",
+		Desc:     "Install haproxy and keepalived",
+		Hosts:    e.Runtime.GetHostsByRole(common.Loadbalancer),
+		Action:   new(InstallHaproxyAndKeepalived),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	haproxyCfg := &task.RemoteTask{
+		Name:  "GenerateHaproxyConfig",
+		Desc:  "Generate haproxy.cfg",
+		Hosts: e.Runtime.GetHostsByRole(common.Loadbalancer),
+		Action: &action.Template{
+			Template: templates.ExternalHaproxyConfig,
+			Dst:      HaproxyServiceConfig,
+			Data: util.Data{
+				"Port":            e.KubeConf.Cluster.ControlPlaneEndpoint.Port,
+				"HealthCheckPort": healthCheckPort,
+				"KubernetesType":  e.KubeConf.Cluster.Kubernetes.Type,
+				"APIServers":      externalAPIServers(e.Runtime, e.PipelineCache),
+			},
+		},
+		Parallel: true,
+	}
+
+	getInterface := &task.RemoteTask{
+		Name:     "GetKeepalivedInterface",
+		Desc:     "Get the interface of the VIP",
+		Hosts:    e.Runtime.GetHostsByRole(common.Loadbalancer),
+		Action:   new(GetKeepalivedInterface),
+		Parallel: true,
+	}
+
+	keepalivedCfg := &task.RemoteTask{
+		Name:     "GenerateKeepalivedConfig",
+		Desc:     "Generate keepalived.conf",
+		Hosts:    e.Runtime.GetHostsByRole(common.Loadbalancer),
+		Action:   new(GenerateKeepalivedConfig),
+		Parallel: true,
+	}
+
+	// Restart the services one by one, so that there is always a node holding the VIP.
+	restart := &task.RemoteTask{
+		Name:     "RestartHaproxyAndKeepalived",
+		Desc:     "Restart haproxy and keepalived",
+		Hosts:    e.Runtime.GetHostsByRole(common.Loadbalancer),
+		Action:   new(RestartHaproxyAndKeepalived),
+		Parallel: false,
+		Retry:    2,
+	}
+
+	e.Tasks = []task.Interface{
+		install,
+		haproxyCfg,
+		getInterface,
+		keepalivedCfg,
+		restart,
+	}
+}
+
+// externalAPIServers returns the apiservers behind the haproxy on the loadbalancer nodes, except the node being deleted.
+func externalAPIServers(runtime connector.ModuleRuntime, pipelineCache *cache.Cache) []templates.APIServer {
+	dstNode, _ := pipelineCache.GetMustString("dstNode")
+	servers := make([]templates.APIServer, 0, len(runtime.GetHostsByRole(common.Master)))
+	for _, server := range templates.APIServers(runtime) {
+		if server.Name == dstNode {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	return servers
+}
+
+type DeleteExternalLoadbalancerModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (d *DeleteExternalLoadbalancerModule) IsSkip() bool {
+	return d.Skip
+}
+
+func (d *DeleteExternalLoadbalancerModule) Init() {
+	d.Name = "DeleteExternalLoadbalancerModule"
+	d.Desc = "Stop haproxy and keepalived on the loadbalancer nodes"
+
+	stop := &task.RemoteTask{
+		Name:     "StopHaproxyAndKeepalived",
+		Desc:     "Stop haproxy and keepalived",
+		Hosts:    d.Runtime.GetHostsByRole(common.Loadbalancer),
+		Action:   new(StopHaproxyAndKeepalived),
+		Parallel: true,
+	}
+
+	d.Tasks = []task.Interface{
+		stop,
+	}
+}
+
 type DeleteVIPModule struct {
 	common.KubeModule
 	Skip bool
@@ -0,0 +1,90 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"path/filepath"
+	"text/template"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/loadbalancer/templates"
+)
+
+const healthCheckPort = 8081
+
+// localProxy is an internal load balancer running as a static pod on each worker,
+// which proxies 127.0.0.1:<port> to the apiservers.
+type localProxy struct {
+	// Image is the name of the image in images.GetImage, ImageData is the name of its reference in the manifest.
+	Image     string
+	ImageData string
+	Dir       string
+	Config    *template.Template
+	Manifest  *template.Template
+}
+
+var localProxies = map[string]*localProxy{
+	kubekeyapiv1alpha2.Haproxy: {
+		Image:     "haproxy",
+		ImageData: "HaproxyImage",
+		Dir:       common.HaproxyDir,
+		Config:    templates.HaproxyConfig,
+		Manifest:  templates.HaproxyManifest,
+	},
+	kubekeyapiv1alpha2.Nginx: {
+		Image:     "nginx",
+		ImageData: "NginxImage",
+		Dir:       common.NginxDir,
+		Config:    templates.NginxConfig,
+		Manifest:  templates.NginxManifest,
+	},
+	kubekeyapiv1alpha2.Envoy: {
+		Image:     "envoy",
+		ImageData: "EnvoyImage",
+		Dir:       common.EnvoyDir,
+		Config:    templates.EnvoyConfig,
+		Manifest:  templates.EnvoyManifest,
+	},
+}
+
+func getLocalProxy(kubeConf *common.KubeConf) *localProxy {
+	if p, ok := localProxies[kubeConf.Cluster.ControlPlaneEndpoint.InternalLoadbalancer]; ok {
+		return p
+	}
+	return localProxies[kubekeyapiv1alpha2.Haproxy]
+}
+
+func (l *localProxy) configPath() string {
+	return filepath.Join(l.Dir, l.Config.Name())
+}
+
+func (l *localProxy) configData(runtime connector.ModuleRuntime, kubeConf *common.KubeConf, port int) util.Data {
+	data := util.Data{
+		"LoadbalancerApiserverPort":            port,
+		"LoadbalancerApiserverHealthcheckPort": healthCheckPort,
+		"KubernetesType":                       kubeConf.Cluster.Kubernetes.Type,
+	}
+	if l.Config == templates.HaproxyConfig {
+		data["MasterNodes"] = templates.MasterNodeStr(runtime, kubeConf)
+	} else {
+		data["MasterNodes"] = templates.APIServers(runtime)
+	}
+	return data
+}
@@ -36,7 +36,7 @@ type GetChecksum struct {
 }
 
 func (g *GetChecksum) Execute(runtime connector.Runtime) error {
-	md5Str, err := runtime.GetRunner().FileMd5(filepath.Join(common.HaproxyDir, "haproxy.cfg"))
+	md5Str, err := runtime.GetRunner().FileMd5(getLocalProxy(g.KubeConf).configPath())
 	if err != nil {
 		return err
 	}
@@ -46,23 +46,36 @@ func (g *GetChecksum) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
-type GenerateHaproxyManifest struct {
+// GenerateLoadbalancerManifest generates the static pod of the internal load balancer, and removes the ones
+// of the other load balancers, which listen on the same port.
+type GenerateLoadbalancerManifest struct {
 	common.KubeAction
+	ManifestDir string
 }
 
-func (g *GenerateHaproxyManifest) Execute(runtime connector.Runtime) error {
+func (g *GenerateLoadbalancerManifest) Execute(runtime connector.Runtime) error {
 	host := runtime.RemoteHost()
 	md5Str, ok := host.GetCache().GetMustString("md5")
 	if !ok {
-		return errors.New("get haproxy config md5 sum by host label failed")
+		return errors.New("get load balancer config md5 sum by host label failed")
+	}
+
+	proxy := getLocalProxy(g.KubeConf)
+	for _, p := range localProxies {
+		if p == proxy {
+			continue
+		}
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s", filepath.Join(g.ManifestDir, p.Manifest.Name())), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "remove the %s manifest failed", p.Image)
+		}
 	}
 
 	templateAction := action.Template{
-		Template: templates.HaproxyManifest,
-		Dst:      filepath.Join(common.KubeManifestDir, templates.HaproxyManifest.Name()),
+		Template: proxy.Manifest,
+		Dst:      filepath.Join(g.ManifestDir, proxy.Manifest.Name()),
 		Data: util.Data{
-			"HaproxyImage":    images.GetImage(runtime, g.KubeConf, "haproxy").ImageName(),
-			"HealthCheckPort": 8081,
+			proxy.ImageData:   images.GetImage(runtime, g.KubeConf, proxy.Image).ImageName(),
+			"HealthCheckPort": healthCheckPort,
 			"Checksum":        md5Str,
 		},
 	}
@@ -217,34 +230,6 @@ func (g *GenerateKubevipManifest) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
-type GenerateK3sHaproxyManifest struct {
-	common.KubeAction
-}
-
-func (g *GenerateK3sHaproxyManifest) Execute(runtime connector.Runtime) error {
-	host := runtime.RemoteHost()
-	md5Str, ok := host.GetCache().GetMustString("md5")
-	if !ok {
-		return errors.New("get haproxy config md5 sum by host label failed")
-	}
-
-	templateAction := action.Template{
-		Template: templates.HaproxyManifest,
-		Dst:      filepath.Join("/var/lib/rancher/k3s/agent/pod-manifests", templates.HaproxyManifest.Name()),
-		Data: util.Data{
-			"HaproxyImage":    images.GetImage(runtime, g.KubeConf, "haproxy").ImageName(),
-			"HealthCheckPort": 8081,
-			"Checksum":        md5Str,
-		},
-	}
-
-	templateAction.Init(nil, nil)
-	if err := templateAction.Execute(runtime); err != nil {
-		return err
-	}
-	return nil
-}
-
 type CreateManifestsFolder struct {
 	action.BaseAction
 }
@@ -312,3 +297,145 @@ func (g *DeleteVIP) Execute(runtime connector.Runtime) error {
 	runtime.GetRunner().SudoCmd(cmd, false)
 	return nil
 }
+
+// InstallHaproxyAndKeepalived installs haproxy and keepalived by the package manager of the loadbalancer node,
+// if they haven't been installed from the repository iso.
+type InstallHaproxyAndKeepalived struct {
+	common.KubeAction
+}
+
+func (i *InstallHaproxyAndKeepalived) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd("command -v haproxy && command -v keepalived", false); err == nil {
+		return nil
+	}
+	cmd := "if command -v apt-get > /dev/null; " +
+		"then apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y haproxy keepalived; " +
+		"else yum install -y haproxy keepalived; fi"
+	if _, err := runtime.GetRunner().SudoCmd(cmd, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "install haproxy and keepalived failed")
+	}
+	return nil
+}
+
+// GetKeepalivedInterface gets the interface which the VIP is bound to, it's the one of the internal address by default.
+type GetKeepalivedInterface struct {
+	common.KubeAction
+}
+
+func (g *GetKeepalivedInterface) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	interfaceName := g.KubeConf.Cluster.ControlPlaneEndpoint.Keepalived.Interface
+	if interfaceName == "" {
+		cmd := fmt.Sprintf("ip -o addr show | awk '$4 ~ \"^%s/\" {print $2}' | head -n 1", host.GetInternalAddress())
+		out, err := runtime.GetRunner().SudoCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+		interfaceName = strings.TrimSpace(out)
+	}
+	if interfaceName == "" {
+		return errors.Errorf("get the interface of %s failed", host.GetInternalAddress())
+	}
+	// type: string
+	host.GetCache().Set("interface", interfaceName)
+	return nil
+}
+
+// GenerateKeepalivedConfig generates the keepalived config of the node, the priority is decreased by the order of
+// the loadbalancer nodes, so the first one holds the VIP as long as its haproxy is running.
+type GenerateKeepalivedConfig struct {
+	common.KubeAction
+}
+
+func (g *GenerateKeepalivedConfig) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	interfaceName, ok := host.GetCache().GetMustString("interface")
+	if !ok {
+		return errors.New("get interface failed")
+	}
+
+	priority := DefaultKeepalivedPriority
+	var peers []string
+	for i, node := range runtime.GetHostsByRole(common.Loadbalancer) {
+		if node.GetName() == host.GetName() {
+			priority = DefaultKeepalivedPriority - i
+			continue
+		}
+		peers = append(peers, node.GetInternalAddress())
+	}
+	state := "BACKUP"
+	if priority == DefaultKeepalivedPriority {
+		state = "MASTER"
+	}
+
+	keepalived := g.KubeConf.Cluster.ControlPlaneEndpoint.Keepalived
+	authPass := keepalived.AuthPass
+	if len(authPass) > 8 {
+		authPass = authPass[:8]
+	}
+
+	checkScript := filepath.Join(KeepalivedConfigDir, templates.KeepalivedCheckScript.Name())
+	scriptAction := action.Template{
+		Template: templates.KeepalivedCheckScript,
+		Dst:      checkScript,
+		Data: util.Data{
+			"HealthCheckPort": healthCheckPort,
+		},
+	}
+	scriptAction.Init(nil, nil)
+	if err := scriptAction.Execute(runtime); err != nil {
+		return err
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("chmod 0755 %s", checkScript), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "chmod %s failed", checkScript)
+	}
+
+	templateAction := action.Template{
+		Template: templates.KeepalivedConfig,
+		Dst:      filepath.Join(KeepalivedConfigDir, templates.KeepalivedConfig.Name()),
+		Data: util.Data{
+			"RouterID":        host.GetName(),
+			"CheckScript":     checkScript,
+			"State":           state,
+			"Interface":       interfaceName,
+			"VirtualRouterID": keepalived.VirtualRouterID,
+			"Priority":        priority,
+			"AuthPass":        authPass,
+			"Address":         host.GetInternalAddress(),
+			"Peers":           peers,
+			"VIP":             g.KubeConf.Cluster.ControlPlaneEndpoint.Address,
+		},
+	}
+	templateAction.Init(nil, nil)
+	if err := templateAction.Execute(runtime); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestartHaproxyAndKeepalived enables the services and reloads them to apply the new configs.
+type RestartHaproxyAndKeepalived struct {
+	common.KubeAction
+}
+
+func (r *RestartHaproxyAndKeepalived) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("haproxy -c -f %s", HaproxyServiceConfig), true); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "check %s failed", HaproxyServiceConfig)
+	}
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl daemon-reload && systemctl enable haproxy keepalived && systemctl reload-or-restart haproxy keepalived", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart haproxy and keepalived failed")
+	}
+	return nil
+}
+
+// StopHaproxyAndKeepalived stops the services on the loadbalancer nodes, the packages are kept.
+type StopHaproxyAndKeepalived struct {
+	common.KubeAction
+}
+
+func (s *StopHaproxyAndKeepalived) Execute(runtime connector.Runtime) error {
+	_, _ = runtime.GetRunner().SudoCmd("systemctl disable --now keepalived haproxy", false)
+	_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s", filepath.Join(KeepalivedConfigDir, templates.KeepalivedCheckScript.Name())), false)
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+)
+
+// EnvoyConfig proxies the apiservers with the tcp proxy. Each apiserver is checked by requesting /healthz over TLS,
+// except for k3s whose apiserver doesn't allow the anonymous request, where only the connection is checked.
+var EnvoyConfig = template.Must(template.New("envoy.yaml").Parse(
+	dedent.Dedent(`
+static_resources:
+  listeners:
+  - name: healthz
+    address:
+      socket_address:
+        address: 0.0.0.0
+        port_value: {{ .LoadbalancerApiserverHealthcheckPort }}
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.http_connection_manager
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
+          stat_prefix: healthz
+          route_config:
+            virtual_hosts:
+            - name: healthz
+              domains: ["*"]
+              routes:
+              - match:
+                  path: /healthz
+                direct_response:
+                  status: 200
+          http_filters:
+          - name: envoy.filters.http.router
+            typed_config:
+              "@type": type.googleapis.com/envoy.extensions.filters.http.router.v3.Router
+  - name: kube_apiserver
+    address:
+      socket_address:
+        address: 127.0.0.1
+        port_value: {{ .LoadbalancerApiserverPort }}
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.tcp_proxy
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy
+          stat_prefix: kube_apiserver
+          cluster: kube_apiserver
+          idle_timeout: 900s
+  clusters:
+  - name: kube_apiserver
+    type: STATIC
+    connect_timeout: 5s
+    lb_policy: LEAST_REQUEST
+    {{- if ne .KubernetesType "k3s" }}
+    transport_socket_matches:
+    - name: health-check
+      match:
+        health-check: tls
+      transport_socket:
+        name: envoy.transport_sockets.tls
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.UpstreamTlsContext
+    - name: default
+      match: {}
+      transport_socket:
+        name: envoy.transport_sockets.raw_buffer
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.transport_sockets.raw_buffer.v3.RawBuffer
+    {{- end }}
+    health_checks:
+    - timeout: 5s
+      interval: 15s
+      unhealthy_threshold: 2
+      healthy_threshold: 2
+      {{- if ne .KubernetesType "k3s" }}
+      http_health_check:
+        path: /healthz
+      transport_socket_match_criteria:
+        health-check: tls
+      {{- else }}
+      tcp_health_check: {}
+      {{- end }}
+    load_assignment:
+      cluster_name: kube_apiserver
+      endpoints:
+      - lb_endpoints:
+        {{- range .MasterNodes }}
+        - endpoint:
+            hostname: {{ .Name }}
+            address:
+              socket_address:
+                address: {{ .Address }}
+                port_value: {{ .Port }}
+        {{- end }}
+`)))
+
+var EnvoyManifest = template.Must(template.New("envoy.yaml").Parse(
+	dedent.Dedent(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: envoy
+  namespace: kube-system
+  labels:
+    addonmanager.kubernetes.io/mode: Reconcile
+    k8s-app: kube-envoy
+  annotations:
+    cfg-checksum: "{{ .Checksum }}"
+spec:
+  hostNetwork: true
+  dnsPolicy: ClusterFirstWithHostNet
+  nodeSelector:
+    kubernetes.io/os: linux
+  priorityClassName: system-node-critical
+  containers:
+  - name: envoy
+    image: {{ .EnvoyImage }}
+    imagePullPolicy: IfNotPresent
+    command:
+    - envoy
+    - --config-path
+    - /etc/envoy/envoy.yaml
+    resources:
+      requests:
+        cpu: 25m
+        memory: 64M
+    livenessProbe:
+      httpGet:
+        path: /healthz
+        port: {{ .HealthCheckPort }}
+    readinessProbe:
+      httpGet:
+        path: /healthz
+        port: {{ .HealthCheckPort }}
+    volumeMounts:
+    - mountPath: /etc/envoy
+      name: etc-envoy
+      readOnly: true
+  volumes:
+  - name: etc-envoy
+    hostPath:
+      path: /etc/kubekey/envoy
+`)))
@@ -72,6 +72,54 @@ backend kube_api_backend
   {{- end }}
 `)))
 
+// ExternalHaproxyConfig is the config of the haproxy service on the loadbalancer nodes, it listens on the VIP port.
+var ExternalHaproxyConfig = template.Must(template.New("haproxy.cfg").Parse(
+	dedent.Dedent(`
+global
+    maxconn                 4000
+    log                     /dev/log local0
+
+defaults
+    mode                    http
+    log                     global
+    option                  httplog
+    option                  dontlognull
+    option                  http-server-close
+    option                  redispatch
+    retries                 5
+    timeout http-request    5m
+    timeout queue           5m
+    timeout connect         30s
+    timeout client          30s
+    timeout server          15m
+    timeout http-keep-alive 30s
+    timeout check           30s
+    maxconn                 4000
+
+frontend healthz
+  bind *:{{ .HealthCheckPort }}
+  mode http
+  monitor-uri /healthz
+
+frontend kube_api_frontend
+  bind *:{{ .Port }}
+  mode tcp
+  option tcplog
+  default_backend kube_api_backend
+
+backend kube_api_backend
+  mode tcp
+  balance leastconn
+  default-server inter 5s downinter 5s rise 2 fall 2 slowstart 60s maxconn 1000 maxqueue 256 weight 100
+  {{- if ne .KubernetesType "k3s"}}
+  option httpchk GET /healthz
+  http-check expect status 200
+  {{- end }}
+  {{- range .APIServers }}
+  server {{ .Name }} {{ .Address }}:{{ .Port }} check check-ssl verify none
+  {{- end }}
+`)))
+
 func MasterNodeStr(runtime connector.ModuleRuntime, conf *common.KubeConf) []string {
 	masterNodes := make([]string, len(runtime.GetHostsByRole(common.Master)))
 	for i, node := range runtime.GetHostsByRole(common.Master) {
@@ -79,3 +127,22 @@ func MasterNodeStr(runtime connector.ModuleRuntime, conf *common.KubeConf) []str
 	}
 	return masterNodes
 }
+
+// APIServer is an apiserver proxied by the internal load balancer.
+type APIServer struct {
+	Name    string
+	Address string
+	Port    int
+}
+
+func APIServers(runtime connector.ModuleRuntime) []APIServer {
+	servers := make([]APIServer, 0, len(runtime.GetHostsByRole(common.Master)))
+	for _, node := range runtime.GetHostsByRole(common.Master) {
+		servers = append(servers, APIServer{
+			Name:    node.GetName(),
+			Address: node.GetAddress(),
+			Port:    kubekeyapiv1alpha2.DefaultApiserverPort,
+		})
+	}
+	return servers
+}
@@ -0,0 +1,73 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+)
+
+// KeepalivedConfig holds the VIP on the loadbalancer node with the highest priority whose haproxy is running.
+var KeepalivedConfig = template.Must(template.New("keepalived.conf").Parse(
+	dedent.Dedent(`
+global_defs {
+  router_id {{ .RouterID }}
+  enable_script_security
+  script_user root
+}
+
+vrrp_script check_haproxy {
+  script "{{ .CheckScript }}"
+  interval 2
+  fall 2
+  rise 2
+}
+
+vrrp_instance kube_apiserver {
+  state {{ .State }}
+  interface {{ .Interface }}
+  virtual_router_id {{ .VirtualRouterID }}
+  priority {{ .Priority }}
+  advert_int 1
+  {{- if .AuthPass }}
+  authentication {
+    auth_type PASS
+    auth_pass {{ .AuthPass }}
+  }
+  {{- end }}
+  unicast_src_ip {{ .Address }}
+  unicast_peer {
+  {{- range .Peers }}
+    {{ . }}
+  {{- end }}
+  }
+  virtual_ipaddress {
+    {{ .VIP }}
+  }
+  track_script {
+    check_haproxy
+  }
+}
+`)))
+
+// KeepalivedCheckScript fails when haproxy is not running, so that the VIP moves to another loadbalancer node.
+var KeepalivedCheckScript = template.Must(template.New("check_haproxy.sh").Parse(
+	dedent.Dedent(`#!/bin/sh
+systemctl is-active --quiet haproxy || exit 1
+curl -sf -o /dev/null http://127.0.0.1:{{ .HealthCheckPort }}/healthz || exit 1
+`)))
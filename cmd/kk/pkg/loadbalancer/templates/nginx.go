@@ -0,0 +1,111 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+)
+
+// NginxConfig proxies the apiservers with the stream module, an apiserver is marked as unavailable after
+// it fails to be connected for max_fails times, and is checked again after fail_timeout.
+var NginxConfig = template.Must(template.New("nginx.conf").Parse(
+	dedent.Dedent(`
+error_log stderr notice;
+
+worker_processes 2;
+worker_rlimit_nofile 130048;
+worker_shutdown_timeout 10s;
+
+events {
+  multi_accept on;
+  use epoll;
+  worker_connections 16384;
+}
+
+stream {
+  upstream kube_apiserver {
+    least_conn;
+    {{- range .MasterNodes }}
+    server {{ .Address }}:{{ .Port }} max_fails=2 fail_timeout=15s;
+    {{- end }}
+  }
+
+  server {
+    listen        127.0.0.1:{{ .LoadbalancerApiserverPort }};
+    proxy_pass    kube_apiserver;
+    proxy_timeout 15m;
+    proxy_connect_timeout 1s;
+  }
+}
+
+http {
+  access_log off;
+
+  server {
+    listen {{ .LoadbalancerApiserverHealthcheckPort }};
+    location /healthz {
+      return 200;
+    }
+  }
+}
+`)))
+
+var NginxManifest = template.Must(template.New("nginx.yaml").Parse(
+	dedent.Dedent(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  namespace: kube-system
+  labels:
+    addonmanager.kubernetes.io/mode: Reconcile
+    k8s-app: kube-nginx
+  annotations:
+    cfg-checksum: "{{ .Checksum }}"
+spec:
+  hostNetwork: true
+  dnsPolicy: ClusterFirstWithHostNet
+  nodeSelector:
+    kubernetes.io/os: linux
+  priorityClassName: system-node-critical
+  containers:
+  - name: nginx
+    image: {{ .NginxImage }}
+    imagePullPolicy: IfNotPresent
+    resources:
+      requests:
+        cpu: 25m
+        memory: 32M
+    livenessProbe:
+      httpGet:
+        path: /healthz
+        port: {{ .HealthCheckPort }}
+    readinessProbe:
+      httpGet:
+        path: /healthz
+        port: {{ .HealthCheckPort }}
+    volumeMounts:
+    - mountPath: /etc/nginx
+      name: etc-nginx
+      readOnly: true
+  volumes:
+  - name: etc-nginx
+    hostPath:
+      path: /etc/kubekey/nginx
+`)))
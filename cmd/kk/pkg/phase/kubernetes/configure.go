@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"github.com/pkg/errors"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/addons"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/certs"
@@ -36,7 +37,7 @@ func NewCreateConfigureKubernetesPipeline(runtime *common.KubeRuntime) error {
 	skipLocalStorage := true
 	if runtime.Arg.DeployLocalStorage != nil {
 		skipLocalStorage = !*runtime.Arg.DeployLocalStorage
-	} else if runtime.Cluster.KubeSphere.Enabled {
+	} else if runtime.Cluster.KubeSphere.Enabled || runtime.Cluster.Storage.DefaultStorageClass == kubekeyapiv1alpha2.OpenEBS {
 		skipLocalStorage = false
 	}
 	m := []module.Module{
@@ -49,6 +50,7 @@ func NewCreateConfigureKubernetesPipeline(runtime *common.KubeRuntime) error {
 		&kubernetes.SaveKubeConfigModule{},
 		&plugins.DeployPluginsModule{},
 		&addons.AddonsModule{},
+		&storage.DeployStorageProvidersModule{Skip: len(runtime.Cluster.Storage.Providers()) == 0},
 		&storage.DeployLocalVolumeModule{Skip: skipLocalStorage},
 	}
 
@@ -61,17 +61,21 @@ func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&etcd.ConfigureModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.BackupModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&kubernetes.InstallKubeBinariesModule{},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
+		&kubernetes.SyncPKIModule{Skip: !runtime.Cluster.Kubernetes.PKI.Enabled()},
 		&kubernetes.JoinNodesModule{},
-		&loadbalancer.HaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		&loadbalancer.InternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "AddNodesPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "AddNodesPipeline",
+		Modules:    m,
+		Runtime:    runtime,
+		ConfigHash: runtime.ConfigHash(),
+		Resume:     runtime.Arg.Resume,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -96,17 +100,20 @@ func NewK3sAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&etcd.ConfigureModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.BackupModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&k3s.InstallKubeBinariesModule{},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
 		&k3s.JoinNodesModule{},
-		&loadbalancer.K3sHaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		&loadbalancer.K3sInternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "AddNodesPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "AddNodesPipeline",
+		Modules:    m,
+		Runtime:    runtime,
+		ConfigHash: runtime.ConfigHash(),
+		Resume:     runtime.Arg.Resume,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -131,17 +138,20 @@ func NewK8eAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&etcd.ConfigureModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.BackupModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&k8e.InstallKubeBinariesModule{},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
 		&k8e.JoinNodesModule{},
-		&loadbalancer.K3sHaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		&loadbalancer.K3sInternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "AddNodesPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "AddNodesPipeline",
+		Modules:    m,
+		Runtime:    runtime,
+		ConfigHash: runtime.ConfigHash(),
+		Resume:     runtime.Arg.Resume,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -21,6 +21,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/addons"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/artifact"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/binaries"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/confirm"
@@ -34,9 +35,12 @@ import (
 func NewArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 	m := []module.Module{
 		&confirm.CheckFileExistModule{FileName: runtime.Arg.Output},
+		&addons.ArtifactAddonsModule{Skip: len(runtime.Spec.Addons) == 0},
 		&images.CopyImagesToLocalModule{},
 		&binaries.ArtifactBinariesModule{},
 		&artifact.RepositoryModule{},
+		&artifact.DeltaModule{Skip: runtime.Arg.Base == ""},
+		&artifact.IndexModule{},
 		&artifact.ArchiveModule{},
 		&filesystem.ChownOutputModule{},
 		&filesystem.ChownWorkDirModule{},
@@ -58,9 +62,12 @@ func NewArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 func NewK3sArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 	m := []module.Module{
 		&confirm.CheckFileExistModule{FileName: runtime.Arg.Output},
+		&addons.ArtifactAddonsModule{Skip: len(runtime.Spec.Addons) == 0},
 		&images.CopyImagesToLocalModule{},
 		&binaries.K3sArtifactBinariesModule{},
 		&artifact.RepositoryModule{},
+		&artifact.DeltaModule{Skip: runtime.Arg.Base == ""},
+		&artifact.IndexModule{},
 		&artifact.ArchiveModule{},
 		&filesystem.ChownOutputModule{},
 		&filesystem.ChownWorkDirModule{},
@@ -82,9 +89,12 @@ func NewK3sArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 func NewK8eArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 	m := []module.Module{
 		&confirm.CheckFileExistModule{FileName: runtime.Arg.Output},
+		&addons.ArtifactAddonsModule{Skip: len(runtime.Spec.Addons) == 0},
 		&images.CopyImagesToLocalModule{},
 		&binaries.K8eArtifactBinariesModule{},
 		&artifact.RepositoryModule{},
+		&artifact.DeltaModule{Skip: runtime.Arg.Base == ""},
+		&artifact.IndexModule{},
 		&artifact.ArchiveModule{},
 		&filesystem.ChownOutputModule{},
 		&filesystem.ChownWorkDirModule{},
@@ -35,6 +35,7 @@ func CheckCertsPipeline(runtime *common.KubeRuntime) error {
 		Name:    "CheckCertsPipeline",
 		Modules: m,
 		Runtime: runtime,
+		Quiet:   runtime.Arg.Output != "" && runtime.Arg.Output != certs.OutputTable,
 	}
 	if err := p.Start(); err != nil {
 		return err
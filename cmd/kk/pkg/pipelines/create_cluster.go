@@ -51,7 +51,7 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 	skipLocalStorage := true
 	if runtime.Arg.DeployLocalStorage != nil {
 		skipLocalStorage = !*runtime.Arg.DeployLocalStorage
-	} else if runtime.Cluster.KubeSphere.Enabled {
+	} else if runtime.Cluster.KubeSphere.Enabled || runtime.Cluster.Storage.DefaultStorageClass == kubekeyapiv1alpha2.OpenEBS {
 		skipLocalStorage = false
 	}
 
@@ -73,13 +73,15 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&etcd.ConfigureModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.BackupModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&kubernetes.InstallKubeBinariesModule{},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
 		&loadbalancer.KubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
+		&kubernetes.SyncPKIModule{Skip: !runtime.Cluster.Kubernetes.PKI.Enabled()},
 		&kubernetes.InitKubernetesModule{},
 		&dns.ClusterDNSModule{},
 		&kubernetes.StatusModule{},
 		&kubernetes.JoinNodesModule{},
 		&loadbalancer.KubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
-		&loadbalancer.HaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		&loadbalancer.InternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&network.DeployNetworkPluginModule{},
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
@@ -88,19 +90,25 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&kubernetes.SaveKubeConfigModule{},
 		&plugins.DeployPluginsModule{},
 		&addons.AddonsModule{},
+		&storage.DeployStorageProvidersModule{Skip: len(runtime.Cluster.Storage.Providers()) == 0},
 		&storage.DeployLocalVolumeModule{Skip: skipLocalStorage},
 		&kubesphere.DeployModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "CreateClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "CreateClusterPipeline",
+		Modules:    m,
+		Runtime:    runtime,
+		ConfigHash: runtime.ConfigHash(),
+		Resume:     runtime.Arg.Resume,
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -131,7 +139,7 @@ func NewK3sCreateClusterPipeline(runtime *common.KubeRuntime) error {
 	skipLocalStorage := true
 	if runtime.Arg.DeployLocalStorage != nil {
 		skipLocalStorage = !*runtime.Arg.DeployLocalStorage
-	} else if runtime.Cluster.KubeSphere.Enabled {
+	} else if runtime.Cluster.KubeSphere.Enabled || runtime.Cluster.Storage.DefaultStorageClass == kubekeyapiv1alpha2.OpenEBS {
 		skipLocalStorage = false
 	}
 
@@ -147,32 +155,39 @@ func NewK3sCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&etcd.InstallETCDBinaryModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.ConfigureModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.BackupModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
 		&loadbalancer.K3sKubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 		&k3s.InstallKubeBinariesModule{},
 		&k3s.InitClusterModule{},
 		&k3s.StatusModule{},
 		&k3s.JoinNodesModule{},
 		&images.CopyImagesToRegistryModule{Skip: skipPushImages},
-		&loadbalancer.K3sHaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		&loadbalancer.K3sInternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&network.DeployNetworkPluginModule{},
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 		&k3s.SaveKubeConfigModule{},
 		&addons.AddonsModule{},
+		&storage.DeployStorageProvidersModule{Skip: len(runtime.Cluster.Storage.Providers()) == 0},
 		&storage.DeployLocalVolumeModule{Skip: skipLocalStorage},
 		&kubesphere.DeployModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "K3sCreateClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "K3sCreateClusterPipeline",
+		Modules:    m,
+		Runtime:    runtime,
+		ConfigHash: runtime.ConfigHash(),
+		Resume:     runtime.Arg.Resume,
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -203,7 +218,7 @@ func NewK8eCreateClusterPipeline(runtime *common.KubeRuntime) error {
 	skipLocalStorage := true
 	if runtime.Arg.DeployLocalStorage != nil {
 		skipLocalStorage = !*runtime.Arg.DeployLocalStorage
-	} else if runtime.Cluster.KubeSphere.Enabled {
+	} else if runtime.Cluster.KubeSphere.Enabled || runtime.Cluster.Storage.DefaultStorageClass == kubekeyapiv1alpha2.OpenEBS {
 		skipLocalStorage = false
 	}
 
@@ -219,32 +234,39 @@ func NewK8eCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&etcd.InstallETCDBinaryModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.ConfigureModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&etcd.BackupModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
 		&loadbalancer.K3sKubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 		&k8e.InstallKubeBinariesModule{},
 		&k8e.InitClusterModule{},
 		&k8e.StatusModule{},
 		&k8e.JoinNodesModule{},
 		&images.CopyImagesToRegistryModule{Skip: skipPushImages},
-		&loadbalancer.K3sHaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		&loadbalancer.K3sInternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&network.DeployNetworkPluginModule{},
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 		&k8e.SaveKubeConfigModule{},
 		&addons.AddonsModule{},
+		&storage.DeployStorageProvidersModule{Skip: len(runtime.Cluster.Storage.Providers()) == 0},
 		&storage.DeployLocalVolumeModule{Skip: skipLocalStorage},
 		&kubesphere.DeployModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "K8eCreateClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "K8eCreateClusterPipeline",
+		Modules:    m,
+		Runtime:    runtime,
+		ConfigHash: runtime.ConfigHash(),
+		Resume:     runtime.Arg.Resume,
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -17,12 +17,14 @@
 package pipelines
 
 import (
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/confirm"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/module"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/etcd"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/kubernetes"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/loadbalancer"
 )
@@ -32,7 +34,10 @@ func DeleteNodePipeline(runtime *common.KubeRuntime) error {
 		&precheck.GreetingsModule{},
 		&confirm.DeleteNodeConfirmModule{},
 		&kubernetes.CompareConfigAndClusterInfoModule{},
+		&etcd.PreCheckModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
+		&etcd.RemoveMemberModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
 		&kubernetes.DeleteKubeNodeModule{},
+		&loadbalancer.ExternalLoadbalancerModule{Skip: len(runtime.GetHostsByRole(common.Loadbalancer)) == 0},
 		&os.ClearNodeOSModule{},
 		&loadbalancer.DeleteVIPModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 	}
@@ -0,0 +1,81 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"github.com/pkg/errors"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/precheck"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/pipeline"
+)
+
+func RotateCAPipeline(runtime *common.KubeRuntime) error {
+	m := []module.Module{
+		&precheck.GreetingsModule{},
+		&certs.RotateCAGenerateModule{},
+		&certs.RotateCATrustModule{},
+		&certs.RotateCALeavesModule{},
+		&certs.RotateCADropModule{},
+		&certs.CheckCertsModule{},
+		&certs.PrintClusterCertsModule{},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "RotateCAPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RotateCA(args common.Argument) error {
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	switch runtime.Cluster.Kubernetes.Type {
+	case common.K3s, common.K8e:
+		return errors.Errorf("rotating the CAs of a %s cluster is not supported, it only works for the cluster created by kubeadm", runtime.Cluster.Kubernetes.Type)
+	}
+	// the new CAs are self-signed, they would replace the ones issued by the external PKI
+	if runtime.Cluster.Kubernetes.PKI.Enabled() {
+		return errors.New("the CAs of the cluster are provided by kubernetes.pki, rotate them in the external PKI instead")
+	}
+	if runtime.Cluster.Etcd.Type == kubekeyapiv1alpha2.KubeKey && runtime.Cluster.Etcd.PKI.Enabled() {
+		return errors.New("the CA of etcd is provided by etcd.pki, rotate it in the external PKI instead")
+	}
+
+	if err := RotateCAPipeline(runtime); err != nil {
+		return err
+	}
+	return nil
+}
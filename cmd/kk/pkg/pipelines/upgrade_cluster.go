@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/artifact"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/binaries"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/confirm"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/certs"
@@ -29,6 +30,8 @@ import (
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/module"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/pipeline"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/filesystem"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/k3s"
+	"github.com/kubesphere/kubekey/cmd/kk/pkg/k8e"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/kubernetes"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/kubesphere"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/loadbalancer"
@@ -43,15 +46,16 @@ func NewUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
 		&precheck.ClusterPreCheckModule{},
 		&confirm.UpgradeConfirmModule{Skip: runtime.Arg.SkipConfirmCheck},
 		&artifact.UnArchiveModule{Skip: noArtifact},
-		&kubernetes.SetUpgradePlanModule{Step: kubernetes.ToV121},
-		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToV121},
-		&loadbalancer.HaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
+		// stop at v1.21.5 to upgrade KubeSphere before upgrading to Kubernetes v1.22 or later
+		&kubernetes.SetUpgradePlanModule{Limit: "v1.21.5", Before: "v1.22.0"},
+		&kubernetes.ProgressiveUpgradeModule{},
+		&loadbalancer.InternalLoadbalancerModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&kubesphere.CleanClusterConfigurationModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 		&kubesphere.ConvertModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 		&kubesphere.DeployModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
-		&kubernetes.SetUpgradePlanModule{Step: kubernetes.ToV122},
-		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToV122},
+		&kubernetes.SetUpgradePlanModule{},
+		&kubernetes.ProgressiveUpgradeModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 	}
@@ -67,6 +71,62 @@ func NewUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
 	return nil
 }
 
+func NewK3sUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
+	noArtifact := runtime.Arg.Artifact == ""
+
+	m := []module.Module{
+		&precheck.GreetingsModule{},
+		&precheck.NodePreCheckModule{},
+		&k3s.StatusModule{},
+		&k3s.UpgradePreCheckModule{},
+		&confirm.UpgradeConfirmModule{Skip: runtime.Arg.SkipConfirmCheck},
+		&artifact.UnArchiveModule{Skip: noArtifact},
+		&binaries.K3sNodeBinariesModule{},
+		&k3s.UpgradeModule{},
+		&kubesphere.DeployModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
+		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
+		&filesystem.ChownModule{},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "K3sUpgradeClusterPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewK8eUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
+	noArtifact := runtime.Arg.Artifact == ""
+
+	m := []module.Module{
+		&precheck.GreetingsModule{},
+		&precheck.NodePreCheckModule{},
+		&k8e.StatusModule{},
+		&k8e.UpgradePreCheckModule{},
+		&confirm.UpgradeConfirmModule{Skip: runtime.Arg.SkipConfirmCheck},
+		&artifact.UnArchiveModule{Skip: noArtifact},
+		&binaries.K8eNodeBinariesModule{},
+		&k8e.UpgradeModule{},
+		&kubesphere.DeployModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
+		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
+		&filesystem.ChownModule{},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "K8eUpgradeClusterPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func UpgradeCluster(args common.Argument, downloadCmd string) error {
 	args.DownloadCommand = func(path, url string) string {
 		// this is an extension point for downloading tools, for example users can set the timeout, proxy or retry under
@@ -92,6 +152,14 @@ func UpgradeCluster(args common.Argument, downloadCmd string) error {
 		if err := NewUpgradeClusterPipeline(runtime); err != nil {
 			return err
 		}
+	case common.K3s:
+		if err := NewK3sUpgradeClusterPipeline(runtime); err != nil {
+			return err
+		}
+	case common.K8e:
+		if err := NewK8eUpgradeClusterPipeline(runtime); err != nil {
+			return err
+		}
 	default:
 		return errors.New("unsupported cluster kubernetes type")
 	}
@@ -17,8 +17,11 @@
 package storage
 
 import (
+	"fmt"
 	"path/filepath"
+	"text/template"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/action"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/prepare"
@@ -55,6 +58,7 @@ func (d *DeployLocalVolumeModule) Init() {
 			Data: util.Data{
 				"ProvisionerLocalPVImage": images.GetImage(d.Runtime, d.KubeConf, "provisioner-localpv").ImageName(),
 				"LinuxUtilsImage":         images.GetImage(d.Runtime, d.KubeConf, "linux-utils").ImageName(),
+				"IsDefault":               d.KubeConf.Cluster.Storage.DefaultProvider() == kubekeyapiv1alpha2.OpenEBS,
 			},
 		},
 		Parallel: true,
@@ -77,3 +81,109 @@ func (d *DeployLocalVolumeModule) Init() {
 		deploy,
 	}
 }
+
+// DeployStorageProvidersModule deploys the storage providers enabled in the storage section of the cluster spec.
+type DeployStorageProvidersModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (d *DeployStorageProvidersModule) IsSkip() bool {
+	return d.Skip
+}
+
+func (d *DeployStorageProvidersModule) Init() {
+	d.Name = "DeployStorageProvidersModule"
+	d.Desc = "Deploy storage providers"
+
+	storage := d.KubeConf.Cluster.Storage
+	defaultProvider := storage.DefaultProvider()
+
+	check := &task.RemoteTask{
+		Name:     "CheckStorageConfig",
+		Desc:     "Check the storage providers config",
+		Hosts:    d.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(CheckStorageConfig),
+		Parallel: true,
+	}
+	d.Tasks = []task.Interface{check}
+
+	if storage.EnableNFS() {
+		d.Tasks = append(d.Tasks, d.deployTasks("NFS", templates.NFS, util.Data{
+			"ProvisionerImage": images.GetImage(d.Runtime, d.KubeConf, "nfs-subdir-external-provisioner").ImageName(),
+			"Server":           storage.NFS.Server,
+			"Path":             storage.NFS.Path,
+			"StorageClassName": storage.NFS.StorageClassName,
+			"MountOptions":     storage.NFS.MountOptions,
+			"ArchiveOnDelete":  storage.NFS.ArchiveOnDelete,
+			"IsDefault":        defaultProvider == kubekeyapiv1alpha2.NFS,
+		})...)
+	}
+
+	if storage.EnableLocalPath() {
+		d.Tasks = append(d.Tasks, d.deployTasks("LocalPath", templates.LocalPath, util.Data{
+			"ProvisionerImage": images.GetImage(d.Runtime, d.KubeConf, "local-path-provisioner").ImageName(),
+			"HelperImage":      images.GetImage(d.Runtime, d.KubeConf, "busybox").ImageName(),
+			"Path":             storage.LocalPath.Path,
+			"StorageClassName": storage.LocalPath.StorageClassName,
+			"IsDefault":        defaultProvider == kubekeyapiv1alpha2.LocalPath,
+		})...)
+	}
+
+	if storage.EnableLonghorn() {
+		// more replicas than nodes leave every volume degraded
+		replicaCount := storage.Longhorn.ReplicaCount
+		if nodes := len(d.Runtime.GetHostsByRole(common.K8s)); replicaCount > nodes {
+			replicaCount = nodes
+		}
+		d.Tasks = append(d.Tasks, d.deployTasks("Longhorn", templates.Longhorn, util.Data{
+			"CRDs":                        templates.LonghornCRDs,
+			"ManagerImage":                images.GetImage(d.Runtime, d.KubeConf, "longhorn-manager").ImageName(),
+			"EngineImage":                 images.GetImage(d.Runtime, d.KubeConf, "longhorn-engine").ImageName(),
+			"InstanceManagerImage":        images.GetImage(d.Runtime, d.KubeConf, "longhorn-instance-manager").ImageName(),
+			"ShareManagerImage":           images.GetImage(d.Runtime, d.KubeConf, "longhorn-share-manager").ImageName(),
+			"BackingImageManagerImage":    images.GetImage(d.Runtime, d.KubeConf, "backing-image-manager").ImageName(),
+			"UIImage":                     images.GetImage(d.Runtime, d.KubeConf, "longhorn-ui").ImageName(),
+			"CSIAttacherImage":            images.GetImage(d.Runtime, d.KubeConf, "longhorn-csi-attacher").ImageName(),
+			"CSIProvisionerImage":         images.GetImage(d.Runtime, d.KubeConf, "longhorn-csi-provisioner").ImageName(),
+			"CSIResizerImage":             images.GetImage(d.Runtime, d.KubeConf, "longhorn-csi-resizer").ImageName(),
+			"CSISnapshotterImage":         images.GetImage(d.Runtime, d.KubeConf, "longhorn-csi-snapshotter").ImageName(),
+			"CSINodeDriverRegistrarImage": images.GetImage(d.Runtime, d.KubeConf, "longhorn-csi-node-registrar").ImageName(),
+			"DataPath":                    storage.Longhorn.DataPath,
+			"ReplicaCount":                replicaCount,
+			"StorageClassName":            storage.Longhorn.StorageClassName,
+			"IsDefault":                   defaultProvider == kubekeyapiv1alpha2.Longhorn,
+		})...)
+	}
+}
+
+func (d *DeployStorageProvidersModule) deployTasks(provider string, tmpl *template.Template, data util.Data) []task.Interface {
+	generate := &task.RemoteTask{
+		Name:    fmt.Sprintf("Generate%sManifest", provider),
+		Desc:    fmt.Sprintf("Generate %s manifest", provider),
+		Hosts:   d.Runtime.GetHostsByRole(common.Master),
+		Prepare: new(common.OnlyFirstMaster),
+		Action: &action.Template{
+			Template: tmpl,
+			Dst:      filepath.Join(common.KubeAddonsDir, tmpl.Name()),
+			Data:     data,
+		},
+		Parallel: true,
+	}
+
+	deploy := &task.RemoteTask{
+		Name:     fmt.Sprintf("Deploy%s", provider),
+		Desc:     fmt.Sprintf("Deploy %s StorageClass", provider),
+		Hosts:    d.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   &DeployManifest{Manifest: tmpl.Name()},
+		Parallel: true,
+		Retry:    5,
+	}
+
+	return []task.Interface{
+		generate,
+		deploy,
+	}
+}
@@ -19,9 +19,11 @@ package storage
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/cmd/kk/pkg/core/connector"
 )
@@ -37,3 +39,41 @@ func (d *DeployLocalVolume) Execute(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+// DeployManifest applies a manifest generated in the addons dir.
+type DeployManifest struct {
+	common.KubeAction
+	Manifest string
+}
+
+func (d *DeployManifest) Execute(runtime connector.Runtime) error {
+	cmd := fmt.Sprintf("/usr/local/bin/kubectl apply -f %s", filepath.Join(common.KubeAddonsDir, d.Manifest))
+	if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "deploy %s failed", d.Manifest)
+	}
+	return nil
+}
+
+type CheckStorageConfig struct {
+	common.KubeAction
+}
+
+func (c *CheckStorageConfig) Execute(_ connector.Runtime) error {
+	storage := c.KubeConf.Cluster.Storage
+	if storage.EnableNFS() && (storage.NFS.Server == "" || storage.NFS.Path == "") {
+		return errors.New("the server and path of the nfs storage are required")
+	}
+
+	defaultProvider := storage.DefaultProvider()
+	if defaultProvider == kubekeyapiv1alpha2.OpenEBS {
+		return nil
+	}
+	for _, p := range storage.Providers() {
+		if p == defaultProvider {
+			return nil
+		}
+	}
+	supported := append([]string{kubekeyapiv1alpha2.OpenEBS}, storage.Providers()...)
+	return errors.Errorf("the default storage class %s is not an enabled storage provider, supported: %s",
+		defaultProvider, strings.Join(supported, ", "))
+}
@@ -0,0 +1,388 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+)
+
+type LonghornCRD struct {
+	Kind      string
+	Singular  string
+	Plural    string
+	ShortName string
+	Status    bool
+}
+
+// LonghornCRDs are the custom resources of longhorn.io/v1beta1. Their schemas are left open, the longhorn manager validates them.
+var LonghornCRDs = []LonghornCRD{
+	{Kind: "BackingImageDataSource", Singular: "backingimagedatasource", Plural: "backingimagedatasources", ShortName: "lhbids", Status: true},
+	{Kind: "BackingImageManager", Singular: "backingimagemanager", Plural: "backingimagemanagers", ShortName: "lhbim", Status: true},
+	{Kind: "BackingImage", Singular: "backingimage", Plural: "backingimages", ShortName: "lhbi", Status: true},
+	{Kind: "Backup", Singular: "backup", Plural: "backups", ShortName: "lhb", Status: true},
+	{Kind: "BackupTarget", Singular: "backuptarget", Plural: "backuptargets", ShortName: "lhbt", Status: true},
+	{Kind: "BackupVolume", Singular: "backupvolume", Plural: "backupvolumes", ShortName: "lhbv", Status: true},
+	{Kind: "EngineImage", Singular: "engineimage", Plural: "engineimages", ShortName: "lhei", Status: true},
+	{Kind: "Engine", Singular: "engine", Plural: "engines", ShortName: "lhe", Status: true},
+	{Kind: "InstanceManager", Singular: "instancemanager", Plural: "instancemanagers", ShortName: "lhim", Status: true},
+	{Kind: "Node", Singular: "node", Plural: "nodes", ShortName: "lhn", Status: true},
+	{Kind: "RecurringJob", Singular: "recurringjob", Plural: "recurringjobs", ShortName: "lhrj", Status: true},
+	{Kind: "Replica", Singular: "replica", Plural: "replicas", ShortName: "lhr", Status: true},
+	{Kind: "Setting", Singular: "setting", Plural: "settings", ShortName: "lhs", Status: false},
+	{Kind: "ShareManager", Singular: "sharemanager", Plural: "sharemanagers", ShortName: "lhsm", Status: true},
+	{Kind: "Volume", Singular: "volume", Plural: "volumes", ShortName: "lhv", Status: true},
+}
+
+// Longhorn defines the template of longhorn's manifests.
+var Longhorn = template.Must(template.New("longhorn.yaml").Parse(
+	dedent.Dedent(`---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: longhorn-system
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: longhorn-service-account
+  namespace: longhorn-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: longhorn-role
+rules:
+- apiGroups:
+  - apiextensions.k8s.io
+  resources:
+  - customresourcedefinitions
+  verbs:
+  - "*"
+- apiGroups: [""]
+  resources: ["pods", "events", "persistentvolumes", "persistentvolumeclaims","persistentvolumeclaims/status", "nodes", "proxy/nodes", "pods/log", "secrets", "services", "endpoints", "configmaps"]
+  verbs: ["*"]
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["get", "list"]
+- apiGroups: ["apps"]
+  resources: ["daemonsets", "statefulsets", "deployments"]
+  verbs: ["*"]
+- apiGroups: ["batch"]
+  resources: ["jobs", "cronjobs"]
+  verbs: ["*"]
+- apiGroups: ["policy"]
+  resources: ["poddisruptionbudgets"]
+  verbs: ["*"]
+- apiGroups: ["scheduling.k8s.io"]
+  resources: ["priorityclasses"]
+  verbs: ["watch", "list"]
+- apiGroups: ["storage.k8s.io"]
+  resources: ["storageclasses", "volumeattachments", "volumeattachments/status", "csinodes", "csidrivers"]
+  verbs: ["*"]
+- apiGroups: ["snapshot.storage.k8s.io"]
+  resources: ["volumesnapshotclasses", "volumesnapshots", "volumesnapshotcontents", "volumesnapshotcontents/status"]
+  verbs: ["*"]
+- apiGroups: ["longhorn.io"]
+  resources: ["*"]
+  verbs: ["*"]
+- apiGroups: ["coordination.k8s.io"]
+  resources: ["leases"]
+  verbs: ["*"]
+- apiGroups: ["metrics.k8s.io"]
+  resources: ["pods", "nodes"]
+  verbs: ["get", "list"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: longhorn-bind
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: longhorn-role
+subjects:
+- kind: ServiceAccount
+  name: longhorn-service-account
+  namespace: longhorn-system
+{{- range .CRDs }}
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  labels:
+    longhorn-manager: ""
+  name: {{ .Plural }}.longhorn.io
+spec:
+  group: longhorn.io
+  names:
+    kind: {{ .Kind }}
+    listKind: {{ .Kind }}List
+    plural: {{ .Plural }}
+    shortNames:
+    - {{ .ShortName }}
+    singular: {{ .Singular }}
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+    served: true
+    storage: true
+    {{- if .Status }}
+    subresources:
+      status: {}
+    {{- end }}
+{{- end }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: longhorn-default-setting
+  namespace: longhorn-system
+data:
+  default-setting.yaml: |-
+    default-data-path: {{ .DataPath }}
+    default-replica-count: {{ .ReplicaCount }}
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  labels:
+    app: longhorn-manager
+  name: longhorn-manager
+  namespace: longhorn-system
+spec:
+  selector:
+    matchLabels:
+      app: longhorn-manager
+  template:
+    metadata:
+      labels:
+        app: longhorn-manager
+    spec:
+      containers:
+      - name: longhorn-manager
+        image: {{ .ManagerImage }}
+        imagePullPolicy: IfNotPresent
+        securityContext:
+          privileged: true
+        command:
+        - longhorn-manager
+        - -d
+        - daemon
+        - --engine-image
+        - {{ .EngineImage }}
+        - --instance-manager-image
+        - {{ .InstanceManagerImage }}
+        - --share-manager-image
+        - {{ .ShareManagerImage }}
+        - --backing-image-manager-image
+        - {{ .BackingImageManagerImage }}
+        - --manager-image
+        - {{ .ManagerImage }}
+        - --service-account
+        - longhorn-service-account
+        ports:
+        - containerPort: 9500
+          name: manager
+        readinessProbe:
+          tcpSocket:
+            port: 9500
+        volumeMounts:
+        - name: dev
+          mountPath: /host/dev/
+        - name: proc
+          mountPath: /host/proc/
+        - name: longhorn
+          mountPath: {{ .DataPath }}
+          mountPropagation: Bidirectional
+        - name: longhorn-default-setting
+          mountPath: /var/lib/longhorn-setting/
+        env:
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+        - name: POD_IP
+          valueFrom:
+            fieldRef:
+              fieldPath: status.podIP
+        - name: NODE_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: spec.nodeName
+        - name: DEFAULT_SETTING_PATH
+          value: /var/lib/longhorn-setting/default-setting.yaml
+      volumes:
+      - name: dev
+        hostPath:
+          path: /dev/
+      - name: proc
+        hostPath:
+          path: /proc/
+      - name: longhorn
+        hostPath:
+          path: {{ .DataPath }}
+      - name: longhorn-default-setting
+        configMap:
+          name: longhorn-default-setting
+      serviceAccountName: longhorn-service-account
+  updateStrategy:
+    rollingUpdate:
+      maxUnavailable: "100%"
+---
+kind: Service
+apiVersion: v1
+metadata:
+  labels:
+    app: longhorn-manager
+  name: longhorn-backend
+  namespace: longhorn-system
+spec:
+  type: ClusterIP
+  sessionAffinity: ClientIP
+  selector:
+    app: longhorn-manager
+  ports:
+  - name: manager
+    port: 9500
+    targetPort: manager
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: longhorn-ui
+  name: longhorn-ui
+  namespace: longhorn-system
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: longhorn-ui
+  template:
+    metadata:
+      labels:
+        app: longhorn-ui
+    spec:
+      containers:
+      - name: longhorn-ui
+        image: {{ .UIImage }}
+        imagePullPolicy: IfNotPresent
+        ports:
+        - containerPort: 8000
+          name: http
+        env:
+        - name: LONGHORN_MANAGER_IP
+          value: "http://longhorn-backend:9500"
+---
+kind: Service
+apiVersion: v1
+metadata:
+  labels:
+    app: longhorn-ui
+  name: longhorn-frontend
+  namespace: longhorn-system
+spec:
+  type: ClusterIP
+  selector:
+    app: longhorn-ui
+  ports:
+  - name: http
+    port: 80
+    targetPort: http
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: longhorn-driver-deployer
+  namespace: longhorn-system
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: longhorn-driver-deployer
+  template:
+    metadata:
+      labels:
+        app: longhorn-driver-deployer
+    spec:
+      initContainers:
+        - name: wait-longhorn-manager
+          image: {{ .ManagerImage }}
+          command: ['sh', '-c', 'while [ $(curl -m 1 -s -o /dev/null -w "%{http_code}" http://longhorn-backend:9500/v1) != "200" ]; do echo waiting; sleep 2; done']
+      containers:
+        - name: longhorn-driver-deployer
+          image: {{ .ManagerImage }}
+          imagePullPolicy: IfNotPresent
+          command:
+          - longhorn-manager
+          - -d
+          - deploy-driver
+          - --manager-image
+          - {{ .ManagerImage }}
+          - --manager-url
+          - http://longhorn-backend:9500/v1
+          env:
+          - name: POD_NAMESPACE
+            valueFrom:
+              fieldRef:
+                fieldPath: metadata.namespace
+          - name: NODE_NAME
+            valueFrom:
+              fieldRef:
+                fieldPath: spec.nodeName
+          - name: SERVICE_ACCOUNT
+            valueFrom:
+              fieldRef:
+                fieldPath: spec.serviceAccountName
+          - name: CSI_ATTACHER_IMAGE
+            value: {{ .CSIAttacherImage }}
+          - name: CSI_PROVISIONER_IMAGE
+            value: {{ .CSIProvisionerImage }}
+          - name: CSI_NODE_DRIVER_REGISTRAR_IMAGE
+            value: {{ .CSINodeDriverRegistrarImage }}
+          - name: CSI_RESIZER_IMAGE
+            value: {{ .CSIResizerImage }}
+          - name: CSI_SNAPSHOTTER_IMAGE
+            value: {{ .CSISnapshotterImage }}
+      serviceAccountName: longhorn-service-account
+      securityContext:
+        runAsUser: 0
+---
+kind: StorageClass
+apiVersion: storage.k8s.io/v1
+metadata:
+  name: {{ .StorageClassName }}
+  annotations:
+    storageclass.kubesphere.io/supported-access-modes: '["ReadWriteOnce","ReadWriteMany"]'
+    storageclass.kubernetes.io/is-default-class: "{{ .IsDefault }}"
+provisioner: driver.longhorn.io
+allowVolumeExpansion: true
+reclaimPolicy: Delete
+volumeBindingMode: Immediate
+parameters:
+  numberOfReplicas: "{{ .ReplicaCount }}"
+  staleReplicaTimeout: "2880"
+  fromBackup: ""
+  fsType: "ext4"
+
+    `)))
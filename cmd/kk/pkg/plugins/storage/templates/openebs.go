@@ -32,7 +32,7 @@ metadata:
   name: local
   annotations:
     storageclass.kubesphere.io/supported-access-modes: '["ReadWriteOnce"]'
-    storageclass.beta.kubernetes.io/is-default-class: "true"
+    storageclass.beta.kubernetes.io/is-default-class: "{{ .IsDefault }}"
     openebs.io/cas-type: local
     cas.openebs.io/config: |
       - name: StorageType
@@ -19,6 +19,7 @@ import (
 	"crypto/elliptic"
 	cryptorand "crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -318,3 +319,31 @@ func EncodeCertPEM(cert *x509.Certificate) []byte {
 	}
 	return pem.EncodeToMemory(&block)
 }
+
+// ReadCertificateAuthorityFiles reads the CA provided by the user, the key is optional. It checks that
+// the certificate is a CA and matches the key, the first certificate of the file is the CA if it's a bundle.
+func ReadCertificateAuthorityFiles(certFile, keyFile string) (*x509.Certificate, []byte, []byte, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "unable to read the CA certificate %s", certFile)
+	}
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "unable to parse the CA certificate %s", certFile)
+	}
+	if !certs[0].IsCA {
+		return nil, nil, nil, errors.Errorf("%s is not a CA certificate", certFile)
+	}
+	if keyFile == "" {
+		return certs[0], certPEM, nil, nil
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "unable to read the CA key %s", keyFile)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "the CA key %s doesn't match the certificate %s", keyFile, certFile)
+	}
+	return certs[0], certPEM, keyPEM, nil
+}
@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
@@ -71,6 +72,61 @@ func VersionSupport(version string) bool {
 	return false
 }
 
+// UpgradePath returns the versions to upgrade through one after another from the current version to the target
+// version. Kubernetes can only be upgraded by one minor version at a time, so the latest supported patch version of
+// each intermediate minor version is added before the target, e.g. v1.22.3 -> v1.24.3 is [v1.23.10 v1.24.3].
+func UpgradePath(current, target string) ([]string, error) {
+	from, err := versionutil.ParseSemantic(current)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the current Kubernetes version %s failed", current)
+	}
+	to, err := versionutil.ParseSemantic(target)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the target Kubernetes version %s failed", target)
+	}
+	if to.LessThan(from) {
+		return nil, errors.Errorf("does not support downgrading Kubernetes from %s to %s", current, target)
+	}
+	if from.Major() != to.Major() {
+		return nil, errors.Errorf("does not support upgrading Kubernetes from %s to %s", current, target)
+	}
+	if !VersionSupport(target) {
+		return nil, errors.Errorf("does not support upgrading to Kubernetes %s", target)
+	}
+
+	path := make([]string, 0, to.Minor()-from.Minor()+1)
+	for minor := from.Minor() + 1; minor < to.Minor(); minor++ {
+		hop := latestPatchVersion(from.Major(), minor)
+		if hop == "" {
+			return nil, errors.Errorf("no supported Kubernetes v%d.%d to upgrade through from %s to %s",
+				from.Major(), minor, current, target)
+		}
+		path = append(path, hop)
+	}
+	if !from.AtLeast(to) {
+		path = append(path, target)
+	}
+	return path, nil
+}
+
+// latestPatchVersion returns the latest supported version of the minor version, or "" if there is none.
+func latestPatchVersion(major, minor uint) string {
+	var latest *versionutil.Version
+	for _, s := range SupportedK8sVersionList() {
+		v := versionutil.MustParseSemantic(s)
+		if v.Major() != major || v.Minor() != minor {
+			continue
+		}
+		if latest == nil || latest.LessThan(v) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return fmt.Sprintf("v%s", latest.String())
+}
+
 // SupportedK8sVersionList returns the supported list of Kubernetes
 func SupportedK8sVersionList() []string {
 	return []string{
@@ -0,0 +1,80 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpgradePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "test_same_minor",
+			current: "v1.22.3",
+			target:  "v1.22.12",
+			want:    []string{"v1.22.12"},
+		},
+		{
+			name:    "test_next_minor",
+			current: "v1.22.3",
+			target:  "v1.23.5",
+			want:    []string{"v1.23.5"},
+		},
+		{
+			name:    "test_skip_level",
+			current: "v1.20.4",
+			target:  "v1.24.3",
+			want:    []string{"v1.21.14", "v1.22.12", "v1.23.10", "v1.24.3"},
+		},
+		{
+			name:    "test_same_version",
+			current: "v1.23.10",
+			target:  "v1.23.10",
+			want:    []string{},
+		},
+		{
+			name:    "test_downgrade",
+			current: "v1.23.10",
+			target:  "v1.22.12",
+			wantErr: true,
+		},
+		{
+			name:    "test_unsupported_target",
+			current: "v1.23.10",
+			target:  "v1.26.0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpgradePath(tt.current, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpgradePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpgradePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
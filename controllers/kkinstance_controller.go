@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -46,6 +47,7 @@ import (
 
 	infrav1 "github.com/kubesphere/kubekey/api/v1beta1"
 	"github.com/kubesphere/kubekey/pkg/clients/ssh"
+	"github.com/kubesphere/kubekey/pkg/clients/ssh/hostkey"
 	"github.com/kubesphere/kubekey/pkg/scope"
 	"github.com/kubesphere/kubekey/pkg/service"
 	"github.com/kubesphere/kubekey/pkg/service/binary"
@@ -75,6 +77,10 @@ type KKInstanceReconciler struct {
 	provisioningFactory     func(sshClient ssh.Interface, format bootstrapv1.Format) service.Provisioning
 	WatchFilterValue        string
 	DataDir                 string
+	// HostKeyPolicy is the policy to verify the host keys of the instances.
+	HostKeyPolicy hostkey.Policy
+	// KnownHostsFile is the known_hosts file of the host key policy, it's recorded into the data dir in tofu mode by default.
+	KnownHostsFile string
 
 	WaitKKInstanceInterval time.Duration
 	WaitKKInstanceTimeout  time.Duration
@@ -84,7 +90,11 @@ func (r *KKInstanceReconciler) getSSHClient(scope *scope.InstanceScope) ssh.Inte
 	if r.sshClientFactory != nil {
 		return r.sshClientFactory(scope)
 	}
-	return ssh.NewClient(scope.KKInstance.Spec.Address, scope.KKInstance.Spec.Auth, &scope.Logger)
+	hostKey := hostkey.Config{Policy: r.HostKeyPolicy, KnownHostsFile: r.KnownHostsFile}
+	if hostKey.KnownHostsFile == "" && hostKey.Policy == hostkey.TOFU {
+		hostKey.KnownHostsFile = filepath.Join(r.DataDir, hostkey.KnownHostsFileName)
+	}
+	return ssh.NewClient(scope.KKInstance.Spec.Address, scope.KKInstance.Spec.Auth, hostKey, &scope.Logger)
 }
 
 func (r *KKInstanceReconciler) getBootstrapService(sshClient ssh.Interface, scope scope.LBScope, instanceScope *scope.InstanceScope) service.Bootstrap {
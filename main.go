@@ -40,6 +40,7 @@ import (
 
 	infrav1 "github.com/kubesphere/kubekey/api/v1beta1"
 	"github.com/kubesphere/kubekey/controllers"
+	"github.com/kubesphere/kubekey/pkg/clients/ssh/hostkey"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -67,6 +68,8 @@ var (
 	syncPeriod              time.Duration
 	watchNamespace          string
 	dataDir                 string
+	hostKeyPolicy           string
+	knownHostsFile          string
 )
 
 func main() {
@@ -79,6 +82,12 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	policy, err := hostkey.ParsePolicy(hostKeyPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid ssh-host-key-policy flag")
+		os.Exit(1)
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 
 	restConfig := ctrl.GetConfigOrDie()
@@ -143,6 +152,8 @@ func main() {
 		Scheme:           mgr.GetScheme(),
 		WatchFilterValue: watchFilterValue,
 		DataDir:          dataDir,
+		HostKeyPolicy:    policy,
+		KnownHostsFile:   knownHostsFile,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: kkInstanceConcurrency, RecoverPanic: true}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KKInstance")
 		os.Exit(1)
@@ -255,4 +266,18 @@ func initFlags(fs *pflag.FlagSet) {
 		"",
 		"The KubeKey data dir.",
 	)
+
+	fs.StringVar(
+		&hostKeyPolicy,
+		"ssh-host-key-policy",
+		string(hostkey.TOFU),
+		"The policy to verify the SSH host keys of the instances: strict (verify against the known hosts file), tofu (trust and record the key of a new host) or insecure (accept any key).",
+	)
+
+	fs.StringVar(
+		&knownHostsFile,
+		"ssh-known-hosts-file",
+		"",
+		"The known hosts file of the SSH host key policy. Defaults to ~/.ssh/known_hosts in strict mode and known_hosts in the data dir in tofu mode.",
+	)
 }
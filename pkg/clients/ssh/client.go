@@ -36,6 +36,7 @@ import (
 	"k8s.io/klog/v2/klogr"
 
 	infrav1 "github.com/kubesphere/kubekey/api/v1beta1"
+	"github.com/kubesphere/kubekey/pkg/clients/ssh/hostkey"
 	"github.com/kubesphere/kubekey/pkg/util/filesystem"
 )
 
@@ -57,13 +58,14 @@ type Client struct {
 	privateKeyPath string
 	timeout        *time.Duration
 	host           string
+	hostKey        hostkey.Config
 	sshClient      *ssh.Client
 	sftpClient     *sftp.Client
 	fs             filesystem.Interface
 }
 
-// NewClient returns a new client given ssh information.
-func NewClient(host string, auth infrav1.Auth, log *logr.Logger) Interface {
+// NewClient returns a new client given ssh information and the host key verification config.
+func NewClient(host string, auth infrav1.Auth, hostKey hostkey.Config, log *logr.Logger) Interface {
 	if log == nil {
 		l := klogr.New()
 		log = &l
@@ -90,6 +92,7 @@ func NewClient(host string, auth infrav1.Auth, log *logr.Logger) Interface {
 		privateKeyPath: auth.PrivateKeyPath,
 		timeout:        auth.Timeout,
 		host:           host,
+		hostKey:        hostKey,
 		fs:             filesystem.NewFileSystem(),
 		Logger:         *log,
 	}
@@ -102,13 +105,16 @@ func (c *Client) Connect() error {
 		return errors.Wrap(err, "The given SSH key could not be parsed")
 	}
 
+	hostKeyCallback, err := c.hostKey.Callback()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the host key callback")
+	}
+
 	sshConfig := &ssh.ClientConfig{
-		User:    c.user,
-		Timeout: *c.timeout,
-		Auth:    authMethods,
-		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-			return nil
-		},
+		User:            c.user,
+		Timeout:         *c.timeout,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
 	}
 
 	endpoint := net.JoinHostPort(c.host, strconv.Itoa(*c.port))
@@ -0,0 +1,156 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package hostkey implements the SSH host key verification policies shared by the kk connector and the ssh client.
+package hostkey
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Policy is the way to verify the host keys of the remote hosts.
+type Policy string
+
+const (
+	// Strict only accepts the host keys which are listed in the known_hosts file.
+	Strict Policy = "strict"
+	// TOFU (trust on first use) records the host key of an unknown host into the known_hosts file,
+	// and rejects the host if its key changes afterwards.
+	TOFU Policy = "tofu"
+	// Insecure accepts any host key.
+	Insecure Policy = "insecure"
+
+	// KnownHostsFileName is the default name of the known_hosts file recorded by TOFU.
+	KnownHostsFileName = "known_hosts"
+)
+
+// locks serializes the updates of the same known_hosts file.
+var locks sync.Map
+
+// ParsePolicy converts the string into a Policy, an empty string is TOFU.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return TOFU, nil
+	case Strict, TOFU, Insecure:
+		return p, nil
+	default:
+		return "", errors.Errorf("invalid host key policy %q, the supported policies are %s, %s and %s", s, Strict, TOFU, Insecure)
+	}
+}
+
+// Config is the host key verification config.
+type Config struct {
+	Policy Policy
+	// KnownHostsFile is the file to verify the host keys against in strict mode, "~/.ssh/known_hosts" by default.
+	// In TOFU mode, it's the file where the host keys are recorded.
+	KnownHostsFile string
+}
+
+// Callback returns the ssh.HostKeyCallback of the policy.
+func (c Config) Callback() (ssh.HostKeyCallback, error) {
+	policy, err := ParsePolicy(string(c.Policy))
+	if err != nil {
+		return nil, err
+	}
+
+	switch policy {
+	case Insecure:
+		return ssh.InsecureIgnoreHostKey(), nil
+	case Strict:
+		path := c.KnownHostsFile
+		if path == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, errors.Wrap(err, "get home dir failed")
+			}
+			path = filepath.Join(home, ".ssh", KnownHostsFileName)
+		}
+		callback, err := knownhosts.New(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load known hosts file %s failed", path)
+		}
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			return describe(callback(hostname, remote, key), hostname, key, path)
+		}, nil
+	default:
+		if c.KnownHostsFile == "" {
+			return nil, errors.New("the known hosts file is required by the tofu host key policy")
+		}
+		return tofu(c.KnownHostsFile), nil
+	}
+}
+
+// tofu returns a callback which trusts and records the key of a host seen for the first time.
+func tofu(path string) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		v, _ := locks.LoadOrStore(path, &sync.Mutex{})
+		mu := v.(*sync.Mutex)
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return errors.Wrapf(err, "create dir of known hosts file %s failed", path)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+		if err != nil {
+			return errors.Wrapf(err, "open known hosts file %s failed", path)
+		}
+		defer f.Close()
+
+		callback, err := knownhosts.New(path)
+		if err != nil {
+			return errors.Wrapf(err, "load known hosts file %s failed", path)
+		}
+		err = callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+			return describe(err, hostname, key, path)
+		}
+
+		line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+		if _, err := fmt.Fprintln(f, line); err != nil {
+			return errors.Wrapf(err, "record the host key of %s into %s failed", hostname, path)
+		}
+		return nil
+	}
+}
+
+// describe adds the fingerprint of the rejected key to the error returned by knownhosts.
+func describe(err error, hostname string, key ssh.PublicKey, path string) error {
+	if err == nil {
+		return nil
+	}
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return err
+	}
+	if len(keyErr.Want) == 0 {
+		return errors.Errorf("host key %s %s of %s is unknown, add it to %s to trust the host",
+			key.Type(), ssh.FingerprintSHA256(key), hostname, path)
+	}
+	return errors.Errorf("host key %s %s of %s does not match the one in %s:%d, the host may have been reinstalled or the connection is intercepted",
+		key.Type(), ssh.FingerprintSHA256(key), hostname, keyErr.Want[0].Filename, keyErr.Want[0].Line)
+}
@@ -0,0 +1,78 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package hostkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newPublicKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestCallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), KnownHostsFileName)
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.0.2"), Port: 22}
+	key := newPublicKey(t)
+
+	if _, err := (Config{Policy: Strict, KnownHostsFile: path}).Callback(); err == nil {
+		t.Fatal("expected an error for the missing known hosts file in strict mode")
+	}
+
+	tofuCallback, err := Config{Policy: TOFU, KnownHostsFile: path}.Callback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tofuCallback("192.168.0.2:22", remote, key); err != nil {
+		t.Fatalf("the first connection should be trusted: %v", err)
+	}
+	if err := tofuCallback("192.168.0.2:22", remote, key); err != nil {
+		t.Fatalf("the recorded key should be accepted: %v", err)
+	}
+	if err := tofuCallback("192.168.0.2:22", remote, newPublicKey(t)); err == nil {
+		t.Fatal("expected an error for the changed host key")
+	}
+
+	strictCallback, err := Config{Policy: Strict, KnownHostsFile: path}.Callback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := strictCallback("192.168.0.2:22", remote, key); err != nil {
+		t.Fatalf("the recorded key should be accepted in strict mode: %v", err)
+	}
+	if err := strictCallback("192.168.0.3:22", remote, key); err == nil {
+		t.Fatal("expected an error for the unknown host in strict mode")
+	}
+
+	if _, err := ParsePolicy("none"); err == nil {
+		t.Fatal("expected an error for the invalid policy")
+	}
+}